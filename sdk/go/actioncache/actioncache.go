@@ -0,0 +1,57 @@
+// Package actioncache defines the remote action-cache interface a runner
+// wires into sykli.Executor via Executor.RegisterCache, so unchanged
+// inputs can be resolved against a shared store instead of every CI
+// fleet member re-running the same task. It mirrors cmd/go/internal/cache's
+// two-table design: an action ID (sykli's own cache_key - see the sykli
+// package's cache.Key) maps to an Entry recording the outcome and, for
+// entries with artifacts, an output ID pointing into the content-addressed
+// Store half of the cache.
+package actioncache
+
+import (
+	"context"
+	"time"
+)
+
+// Entry is one cached task outcome.
+type Entry struct {
+	// OutputID identifies this entry's content in a Store, for task kinds
+	// that produce one (a runner is free to leave this empty for tasks
+	// whose only observable output is Output/ExitCode).
+	OutputID string
+	Success  bool
+	ExitCode int
+	Output   string // captured stdout/stderr, same as sykli.Result.Output
+	// Artifacts maps an output name (matching Task.Output's name) to the
+	// Store object hash holding that output's content.
+	Artifacts map[string]string
+	// CreatedAt is when this entry was stored - used to age out negative
+	// (Success == false) entries under NegativeTTL.
+	CreatedAt time.Time
+}
+
+// Cache looks up and stores Entries by action ID. Get returning ok ==
+// false means a miss - including an expired negative result, see
+// NegativeTTL - and the caller should run the task and Put the outcome.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(ctx context.Context, actionID string) (Entry, bool, error)
+	Put(ctx context.Context, actionID string, entry Entry) error
+}
+
+// Store is the content-addressable half of the cache: the blobs an
+// Entry's Artifacts point to. Kept separate from Cache so a runner
+// backing the action table and the blob store with different systems
+// (e.g. a database for the former, S3 for the latter) doesn't have to
+// implement both behind one type.
+type Store interface {
+	GetObject(ctx context.Context, hash string) ([]byte, bool, error)
+	PutObject(ctx context.Context, hash string, data []byte) error
+}
+
+// Backend is both halves of the cache - what FileCache and HTTPCache each
+// implement.
+type Backend interface {
+	Cache
+	Store
+}
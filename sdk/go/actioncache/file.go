@@ -0,0 +1,80 @@
+package actioncache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// fileBackend implements Backend over a directory on disk: action entries
+// are JSON files under <dir>/ac/, content-addressed blobs are raw files
+// under <dir>/cas/ - the same /ac/<hex> and /cas/<hex> layout HTTPCache
+// serves over HTTP, so a FileCache directory can be reused as the backing
+// store behind one.
+type fileBackend struct {
+	dir string
+	opt options
+}
+
+// FileCache returns a Backend rooted at dir, creating the ac/ and cas/
+// subdirectories lazily on first write.
+func FileCache(dir string, opts ...Option) Backend {
+	return &fileBackend{dir: dir, opt: buildOptions(opts)}
+}
+
+func (f *fileBackend) Get(ctx context.Context, actionID string) (Entry, bool, error) {
+	data, err := os.ReadFile(filepath.Join(f.dir, "ac", actionID))
+	if os.IsNotExist(err) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("actioncache: reading %q: %w", actionID, err)
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return Entry{}, false, fmt.Errorf("actioncache: decoding %q: %w", actionID, err)
+	}
+	if f.opt.expired(entry) {
+		return Entry{}, false, nil
+	}
+	return entry, true, nil
+}
+
+func (f *fileBackend) Put(ctx context.Context, actionID string, entry Entry) error {
+	dir := filepath.Join(f.dir, "ac")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("actioncache: creating %q: %w", dir, err)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("actioncache: encoding %q: %w", actionID, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, actionID), data, 0o644); err != nil {
+		return fmt.Errorf("actioncache: writing %q: %w", actionID, err)
+	}
+	return nil
+}
+
+func (f *fileBackend) GetObject(ctx context.Context, hash string) ([]byte, bool, error) {
+	data, err := os.ReadFile(filepath.Join(f.dir, "cas", hash))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("actioncache: reading object %q: %w", hash, err)
+	}
+	return data, true, nil
+}
+
+func (f *fileBackend) PutObject(ctx context.Context, hash string, data []byte) error {
+	dir := filepath.Join(f.dir, "cas")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("actioncache: creating %q: %w", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, hash), data, 0o644); err != nil {
+		return fmt.Errorf("actioncache: writing object %q: %w", hash, err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package actioncache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFileCacheRoundTrip(t *testing.T) {
+	c := FileCache(t.TempDir())
+	ctx := context.Background()
+
+	if _, ok, err := c.Get(ctx, "abc123"); err != nil || ok {
+		t.Fatalf("expected miss on empty cache, got ok=%v err=%v", ok, err)
+	}
+
+	entry := Entry{Success: true, ExitCode: 0, Output: "ok", CreatedAt: time.Now()}
+	if err := c.Put(ctx, "abc123", entry); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, ok, err := c.Get(ctx, "abc123")
+	if err != nil || !ok {
+		t.Fatalf("expected hit, got ok=%v err=%v", ok, err)
+	}
+	if got.Output != "ok" || !got.Success {
+		t.Errorf("unexpected entry: %+v", got)
+	}
+}
+
+func TestFileCacheObjectRoundTrip(t *testing.T) {
+	c := FileCache(t.TempDir())
+	ctx := context.Background()
+
+	if err := c.PutObject(ctx, "deadbeef", []byte("payload")); err != nil {
+		t.Fatalf("PutObject: %v", err)
+	}
+	data, ok, err := c.GetObject(ctx, "deadbeef")
+	if err != nil || !ok || string(data) != "payload" {
+		t.Fatalf("unexpected GetObject result: data=%q ok=%v err=%v", data, ok, err)
+	}
+}
+
+func TestFileCacheNegativeEntryExpiresWithoutTTL(t *testing.T) {
+	c := FileCache(t.TempDir())
+	ctx := context.Background()
+
+	if err := c.Put(ctx, "failed-task", Entry{Success: false, CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := c.Get(ctx, "failed-task"); err != nil || ok {
+		t.Fatalf("expected a failed entry to miss with no NegativeTTL set, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestFileCacheNegativeEntryHonorsTTL(t *testing.T) {
+	c := FileCache(t.TempDir(), NegativeTTL(time.Hour))
+	ctx := context.Background()
+
+	if err := c.Put(ctx, "failed-task", Entry{Success: false, CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := c.Get(ctx, "failed-task"); err != nil || !ok {
+		t.Fatalf("expected a fresh failed entry to hit within NegativeTTL, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestFileCacheNegativeEntryExpiresPastTTL(t *testing.T) {
+	c := FileCache(t.TempDir(), NegativeTTL(time.Millisecond))
+	ctx := context.Background()
+
+	if err := c.Put(ctx, "failed-task", Entry{Success: false, CreatedAt: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := c.Get(ctx, "failed-task"); err != nil || ok {
+		t.Fatalf("expected an old failed entry to miss past NegativeTTL, got ok=%v err=%v", ok, err)
+	}
+}
@@ -0,0 +1,103 @@
+package actioncache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// httpBackend implements Backend against a server exposing
+// GET/PUT <baseURL>/ac/<actionID> (JSON-encoded Entry) and
+// GET/PUT <baseURL>/cas/<hash> (raw bytes) - the same layout FileCache
+// lays out on disk, so either can back the other.
+type httpBackend struct {
+	baseURL string
+	client  *http.Client
+	opt     options
+}
+
+// HTTPCache returns a Backend talking to a server at baseURL implementing
+// the /ac and /cas endpoints described above.
+func HTTPCache(baseURL string, opts ...Option) Backend {
+	return &httpBackend{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  http.DefaultClient,
+		opt:     buildOptions(opts),
+	}
+}
+
+func (h *httpBackend) Get(ctx context.Context, actionID string) (Entry, bool, error) {
+	data, ok, err := h.get(ctx, "/ac/"+actionID)
+	if err != nil || !ok {
+		return Entry{}, ok, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return Entry{}, false, fmt.Errorf("actioncache: decoding %q: %w", actionID, err)
+	}
+	if h.opt.expired(entry) {
+		return Entry{}, false, nil
+	}
+	return entry, true, nil
+}
+
+func (h *httpBackend) Put(ctx context.Context, actionID string, entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("actioncache: encoding %q: %w", actionID, err)
+	}
+	return h.put(ctx, "/ac/"+actionID, data)
+}
+
+func (h *httpBackend) GetObject(ctx context.Context, hash string) ([]byte, bool, error) {
+	return h.get(ctx, "/cas/"+hash)
+}
+
+func (h *httpBackend) PutObject(ctx context.Context, hash string, data []byte) error {
+	return h.put(ctx, "/cas/"+hash, data)
+}
+
+func (h *httpBackend) get(ctx context.Context, path string) ([]byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.baseURL+path, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("actioncache: building request for %q: %w", path, err)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("actioncache: GET %q: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("actioncache: GET %q: unexpected status %s", path, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("actioncache: reading response for %q: %w", path, err)
+	}
+	return data, true, nil
+}
+
+func (h *httpBackend) put(ctx context.Context, path string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, h.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("actioncache: building request for %q: %w", path, err)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("actioncache: PUT %q: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("actioncache: PUT %q: unexpected status %s", path, resp.Status)
+	}
+	return nil
+}
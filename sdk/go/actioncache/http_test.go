@@ -0,0 +1,113 @@
+package actioncache
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// backendHandler serves a Backend's /ac and /cas endpoints over HTTP,
+// standing in for the server a runner would put behind HTTPCache - this
+// package only ships the client half.
+func backendHandler(b Backend) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var isAC bool
+		var key string
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/ac/"):
+			isAC, key = true, strings.TrimPrefix(r.URL.Path, "/ac/")
+		case strings.HasPrefix(r.URL.Path, "/cas/"):
+			isAC, key = false, strings.TrimPrefix(r.URL.Path, "/cas/")
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			var data []byte
+			var ok bool
+			var err error
+			if isAC {
+				var entry Entry
+				entry, ok, err = b.Get(r.Context(), key)
+				if err == nil && ok {
+					data, err = json.Marshal(entry)
+				}
+			} else {
+				data, ok, err = b.GetObject(r.Context(), key)
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(data)
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if isAC {
+				var entry Entry
+				err := json.Unmarshal(body, &entry)
+				if err == nil {
+					err = b.Put(r.Context(), key, entry)
+				}
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			} else if err := b.PutObject(r.Context(), key, body); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// TestHTTPCacheRoundTripAgainstFileCache serves a FileCache's /ac and /cas
+// endpoints over HTTP and exercises HTTPCache against it, the same
+// round-trip FileCache's own tests cover, to confirm the two back ends
+// agree on the wire shape.
+func TestHTTPCacheRoundTripAgainstFileCache(t *testing.T) {
+	fc := FileCache(t.TempDir())
+	srv := httptest.NewServer(backendHandler(fc))
+	defer srv.Close()
+
+	hc := HTTPCache(srv.URL)
+	ctx := context.Background()
+
+	if _, ok, err := hc.Get(ctx, "abc123"); err != nil || ok {
+		t.Fatalf("expected miss, got ok=%v err=%v", ok, err)
+	}
+
+	entry := Entry{Success: true, ExitCode: 0, Output: "ok", CreatedAt: time.Now()}
+	if err := hc.Put(ctx, "abc123", entry); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	got, ok, err := hc.Get(ctx, "abc123")
+	if err != nil || !ok || !got.Success || got.Output != "ok" {
+		t.Fatalf("unexpected Get result: got=%+v ok=%v err=%v", got, ok, err)
+	}
+
+	if err := hc.PutObject(ctx, "deadbeef", []byte("payload")); err != nil {
+		t.Fatalf("PutObject: %v", err)
+	}
+	data, ok, err := hc.GetObject(ctx, "deadbeef")
+	if err != nil || !ok || string(data) != "payload" {
+		t.Fatalf("unexpected GetObject result: data=%q ok=%v err=%v", data, ok, err)
+	}
+}
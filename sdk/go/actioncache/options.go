@@ -0,0 +1,39 @@
+package actioncache
+
+import "time"
+
+// Option configures a FileCache or HTTPCache.
+type Option func(*options)
+
+type options struct {
+	negativeTTL time.Duration
+}
+
+// NegativeTTL bounds how long a cached failure is replayed before the
+// task is retried instead of being skipped again. Zero, the default,
+// means a failed task's entry is never reused - Get always misses on it,
+// so the task is retried every run.
+func NegativeTTL(d time.Duration) Option {
+	return func(o *options) { o.negativeTTL = d }
+}
+
+func buildOptions(opts []Option) options {
+	var o options
+	for _, fn := range opts {
+		fn(&o)
+	}
+	return o
+}
+
+// expired reports whether e should be treated as a miss: a successful
+// entry never expires, a failed one expires immediately with no TTL set,
+// and otherwise expires once its age passes negativeTTL.
+func (o options) expired(e Entry) bool {
+	if e.Success {
+		return false
+	}
+	if o.negativeTTL <= 0 {
+		return true
+	}
+	return time.Since(e.CreatedAt) > o.negativeTTL
+}
@@ -0,0 +1,96 @@
+package sykli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// =============================================================================
+// ARTIFACT STORE
+// =============================================================================
+
+// ArtifactStore describes the shared object store runners use to move
+// artifacts between tasks: Output(...) becomes an upload after the
+// producing task, InputFrom(...) becomes a download before the consuming
+// task, mirroring Tekton's bucket-backed PipelineResource transport.
+type ArtifactStore struct {
+	kind   string // "s3", "gcs", or "local"
+	bucket string // s3/gcs only
+	path   string // local only
+	region string // s3 only
+}
+
+// ArtifactStoreOption configures an ArtifactStore at construction time.
+type ArtifactStoreOption func(*ArtifactStore)
+
+// WithRegion sets the bucket's region. Only meaningful for S3.
+func WithRegion(region string) ArtifactStoreOption {
+	return func(s *ArtifactStore) {
+		s.region = region
+	}
+}
+
+// S3 configures an artifact store backed by an S3 bucket.
+func S3(bucket string, opts ...ArtifactStoreOption) *ArtifactStore {
+	if bucket == "" {
+		log.Panic().Msg("S3: bucket cannot be empty")
+	}
+	s := &ArtifactStore{kind: "s3", bucket: bucket}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GCS configures an artifact store backed by a Google Cloud Storage bucket.
+func GCS(bucket string, opts ...ArtifactStoreOption) *ArtifactStore {
+	if bucket == "" {
+		log.Panic().Msg("GCS: bucket cannot be empty")
+	}
+	s := &ArtifactStore{kind: "gcs", bucket: bucket}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// LocalFS configures an artifact store backed by a shared local directory,
+// useful for single-host runs and tests.
+func LocalFS(path string) *ArtifactStore {
+	if path == "" {
+		log.Panic().Msg("LocalFS: path cannot be empty")
+	}
+	return &ArtifactStore{kind: "local", path: path}
+}
+
+// ArtifactStore registers the pipeline-wide artifact transport. Tasks with
+// Output(...) get an upload step injected after them; tasks with
+// InputFrom(...) get a download step injected before them.
+func (p *Pipeline) ArtifactStore(store *ArtifactStore) *Pipeline {
+	if store == nil {
+		log.Panic().Msg("ArtifactStore: store cannot be nil")
+	}
+	p.artifactStore = store
+	return p
+}
+
+// contentKey derives a stable, content-addressable key for a task's named
+// output from the producing task's name, the output name, and its declared
+// input patterns. It is a plan-time placeholder - the runner is expected to
+// fold in the real content hash of the artifact once it exists on disk -
+// but it gives callers a deterministic key to key cache entries and
+// upload/download steps off before the first real run.
+func contentKey(taskName, outputName string, inputs []string) string {
+	sorted := append([]string(nil), inputs...)
+	sort.Strings(sorted)
+
+	h := sha256.New()
+	h.Write([]byte(taskName))
+	h.Write([]byte{0})
+	h.Write([]byte(outputName))
+	h.Write([]byte{0})
+	h.Write([]byte(strings.Join(sorted, ",")))
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))[:16]
+}
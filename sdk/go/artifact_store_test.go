@@ -0,0 +1,98 @@
+package sykli
+
+import "testing"
+
+func TestArtifactStoreS3Emitted(t *testing.T) {
+	p := New()
+	p.ArtifactStore(S3("my-bucket", WithRegion("us-east-1")))
+	p.Task("build").Run("go build -o /out/app").Output("binary", "/out/app")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := result["artifact_store"].(map[string]interface{})
+	if store["type"] != "s3" || store["bucket"] != "my-bucket" || store["region"] != "us-east-1" {
+		t.Errorf("unexpected artifact_store: %+v", store)
+	}
+}
+
+func TestArtifactStoreLocalFSEmitted(t *testing.T) {
+	p := New()
+	p.ArtifactStore(LocalFS("/tmp/artifacts"))
+	p.Task("build").Run("go build -o /out/app")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := result["artifact_store"].(map[string]interface{})
+	if store["type"] != "local" || store["path"] != "/tmp/artifacts" {
+		t.Errorf("unexpected artifact_store: %+v", store)
+	}
+}
+
+func TestArtifactStoreOmittedWhenUnset(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build -o /out/app")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := result["artifact_store"]; ok {
+		t.Error("expected artifact_store to be omitted when not set")
+	}
+}
+
+func TestArtifactStoreOutputKeysEmittedWhenConfigured(t *testing.T) {
+	p := New()
+	p.ArtifactStore(GCS("bucket"))
+	p.Task("build").Inputs("src/**").Run("go build -o /out/app").Output("binary", "/out/app")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := findTaskByName(result, "build")["output_keys"].(map[string]interface{})
+	if keys["binary"] == "" || keys["binary"] == nil {
+		t.Errorf("expected a non-empty content key for output %q, got %v", "binary", keys["binary"])
+	}
+}
+
+func TestArtifactStoreTaskInputKeyMatchesSourceOutputKey(t *testing.T) {
+	p := New()
+	p.ArtifactStore(GCS("bucket"))
+	p.Task("build").Inputs("src/**").Run("go build -o /out/app").Output("binary", "/out/app")
+	p.Task("package").InputFrom("build", "binary", "/app").Run("docker build .")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buildKey := findTaskByName(result, "build")["output_keys"].(map[string]interface{})["binary"]
+	taskInputs := findTaskByName(result, "package")["task_inputs"].([]interface{})
+	taskInputKey := taskInputs[0].(map[string]interface{})["key"]
+	if buildKey != taskInputKey {
+		t.Errorf("expected matching content keys, got build=%v package=%v", buildKey, taskInputKey)
+	}
+}
+
+func TestArtifactStoreNilPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for nil ArtifactStore")
+		}
+	}()
+	p := New()
+	p.ArtifactStore(nil)
+}
+
+func TestArtifactStoreEmptyBucketPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty S3 bucket")
+		}
+	}()
+	S3("")
+}
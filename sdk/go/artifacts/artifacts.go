@@ -0,0 +1,18 @@
+// Package artifacts defines the provider interface runners use to move
+// task artifacts through the object store configured via
+// sykli.Pipeline.ArtifactStore. The sykli SDK only emits the store's
+// configuration into the pipeline JSON; the actual upload/download happens
+// in the runner, which picks a Provider matching the configured store type.
+package artifacts
+
+import "context"
+
+// Provider uploads and downloads artifacts by content-addressable key.
+// Implementations must be safe for concurrent use, since a single pipeline
+// run may upload and download several artifacts at once.
+type Provider interface {
+	// Upload copies the file at srcPath into the store under key.
+	Upload(ctx context.Context, key, srcPath string) error
+	// Download copies the file stored under key to destPath.
+	Download(ctx context.Context, key, destPath string) error
+}
@@ -0,0 +1,77 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalProvider implements Provider over a shared directory on disk,
+// matching sykli.LocalFS. It's the only Provider this package implements
+// directly - S3 and GCS require their respective cloud SDKs, which belong
+// in the runner, not this SDK.
+type LocalProvider struct {
+	root string
+}
+
+// NewLocalProvider returns a Provider rooted at dir. Keys are joined onto
+// dir as relative paths, so keys must not escape it via "..".
+func NewLocalProvider(dir string) *LocalProvider {
+	return &LocalProvider{root: dir}
+}
+
+// Upload copies the file at srcPath to key's location under the provider's
+// root directory, creating parent directories as needed.
+func (l *LocalProvider) Upload(ctx context.Context, key, srcPath string) error {
+	dest, err := l.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("artifacts: creating %q: %w", filepath.Dir(dest), err)
+	}
+	return copyFile(srcPath, dest)
+}
+
+// Download copies the file stored under key to destPath.
+func (l *LocalProvider) Download(ctx context.Context, key, destPath string) error {
+	src, err := l.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return fmt.Errorf("artifacts: creating %q: %w", filepath.Dir(destPath), err)
+	}
+	return copyFile(src, destPath)
+}
+
+func (l *LocalProvider) resolve(key string) (string, error) {
+	dest := filepath.Join(l.root, key)
+	rel, err := filepath.Rel(l.root, dest)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("artifacts: key %q escapes store root", key)
+	}
+	return dest, nil
+}
+
+func copyFile(srcPath, destPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("artifacts: opening %q: %w", srcPath, err)
+	}
+	defer src.Close()
+
+	dest, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("artifacts: creating %q: %w", destPath, err)
+	}
+	defer dest.Close()
+
+	if _, err := io.Copy(dest, src); err != nil {
+		return fmt.Errorf("artifacts: copying %q to %q: %w", srcPath, destPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package artifacts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalProviderUploadDownloadRoundTrip(t *testing.T) {
+	srcDir := t.TempDir()
+	storeDir := t.TempDir()
+	destDir := t.TempDir()
+
+	srcPath := filepath.Join(srcDir, "app")
+	if err := os.WriteFile(srcPath, []byte("binary contents"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewLocalProvider(storeDir)
+	if err := p.Upload(context.Background(), "build/binary", srcPath); err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+
+	destPath := filepath.Join(destDir, "app")
+	if err := p.Download(context.Background(), "build/binary", destPath); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+
+	got, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "binary contents" {
+		t.Errorf("expected %q, got %q", "binary contents", got)
+	}
+}
+
+func TestLocalProviderRejectsEscapingKey(t *testing.T) {
+	storeDir := t.TempDir()
+	p := NewLocalProvider(storeDir)
+	if err := p.Upload(context.Background(), "../escape", "/dev/null"); err == nil {
+		t.Error("expected error for key escaping store root")
+	}
+}
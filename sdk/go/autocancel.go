@@ -0,0 +1,76 @@
+package sykli
+
+// =============================================================================
+// AUTO-CANCEL
+// =============================================================================
+
+// CancelScope controls what "same pipeline" means when deciding whether a
+// new run supersedes an older one.
+type CancelScope string
+
+const (
+	// ScopeBranch keys superseded-run lookups by branch name.
+	ScopeBranch CancelScope = "branch"
+	// ScopePullRequest keys superseded-run lookups by PR number.
+	ScopePullRequest CancelScope = "pull_request"
+)
+
+// AutoCancelPolicy describes when the runner should cancel in-flight runs
+// of this pipeline whose triggering commit is an ancestor of a newer one.
+type AutoCancelPolicy struct {
+	// OnPush cancels superseded runs triggered by a branch push.
+	OnPush bool
+	// OnPullRequest cancels superseded runs triggered by a pull request update.
+	OnPullRequest bool
+	// Scope selects the key superseded runs are grouped by.
+	Scope CancelScope
+
+	// Pending cancels queued-but-not-yet-started superseded runs.
+	Pending bool
+	// Running cancels already-running superseded runs.
+	Running bool
+	// PullRequests extends Pending/Running cancellation to pull-request-triggered runs.
+	PullRequests bool
+}
+
+// AutoCancel registers a pipeline-wide auto-cancel policy, emitted as a top
+// level `auto_cancel` object. The runner uses the commit graph (not raw
+// timestamps) to decide whether a run supersedes another, so out-of-order
+// webhook delivery can't cancel a newer run.
+//
+// At least one of Pending, Running, OnPush, or OnPullRequest must be set -
+// an AutoCancel call that cancels nothing is almost always a mistake.
+// Auto-cancel never applies to a task marked Task.NoAutoCancel() (or the
+// equivalent NonCancelable()), and documented guidance is to never enable
+// it for a `When("branch == 'main'")` task: a half-applied main deploy is
+// worse than a slow one.
+func (p *Pipeline) AutoCancel(policy AutoCancelPolicy) *Pipeline {
+	switch policy.Scope {
+	case ScopeBranch, ScopePullRequest, "":
+	default:
+		log.Panic().Str("scope", string(policy.Scope)).Msg("AutoCancel: invalid scope")
+	}
+	if policy.Scope == "" {
+		policy.Scope = ScopeBranch
+	}
+	if !policy.Pending && !policy.Running && !policy.OnPush && !policy.OnPullRequest {
+		log.Panic().Msg("AutoCancel: at least one of Pending, Running, OnPush, or OnPullRequest must be true")
+	}
+	p.autoCancel = &policy
+	return p
+}
+
+// NonCancelable opts this task out of the pipeline's AutoCancel policy.
+// Long-running deploys that would leave infrastructure in a half-applied
+// state must be allowed to finish even when a newer commit supersedes the
+// run.
+func (t *Task) NonCancelable() *Task {
+	t.nonCancelable = true
+	return t
+}
+
+// NoAutoCancel is an alias for NonCancelable, matching the naming used by
+// the pipeline-level AutoCancel policy it opts out of.
+func (t *Task) NoAutoCancel() *Task {
+	return t.NonCancelable()
+}
@@ -0,0 +1,43 @@
+package sykli
+
+import "testing"
+
+func TestAutoCancelPendingRunningEmitted(t *testing.T) {
+	p := New()
+	p.AutoCancel(AutoCancelPolicy{Pending: true, Running: true, PullRequests: true})
+	p.Task("test").Run("go test ./...")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ac := result["auto_cancel"].(map[string]interface{})
+	if ac["pending"] != true || ac["running"] != true || ac["pull_requests"] != true {
+		t.Errorf("unexpected auto_cancel: %+v", ac)
+	}
+}
+
+func TestAutoCancelNoFlagsPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic when no sub-flag is set")
+		}
+	}()
+	p := New()
+	p.AutoCancel(AutoCancelPolicy{})
+}
+
+func TestNoAutoCancelAliasesNonCancelable(t *testing.T) {
+	p := New()
+	p.AutoCancel(AutoCancelPolicy{Running: true})
+	p.Task("deploy").Run("./deploy.sh").NoAutoCancel()
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := findTaskByName(result, "deploy")
+	if task["non_cancelable"] != true {
+		t.Errorf("expected non_cancelable=true via NoAutoCancel, got %v", task["non_cancelable"])
+	}
+}
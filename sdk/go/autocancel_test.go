@@ -0,0 +1,56 @@
+package sykli
+
+import "testing"
+
+func TestAutoCancelEmitted(t *testing.T) {
+	p := New()
+	p.AutoCancel(AutoCancelPolicy{OnPush: true, OnPullRequest: true, Scope: ScopeBranch})
+	p.Task("test").Run("go test ./...")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ac := result["auto_cancel"].(map[string]interface{})
+	if ac["on_push"] != true || ac["on_pull_request"] != true || ac["scope"] != "branch" {
+		t.Errorf("unexpected auto_cancel: %+v", ac)
+	}
+}
+
+func TestAutoCancelOmittedWhenUnset(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test ./...")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := result["auto_cancel"]; ok {
+		t.Error("expected no auto_cancel field when AutoCancel was never called")
+	}
+}
+
+func TestAutoCancelInvalidScopePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for invalid scope")
+		}
+	}()
+	p := New()
+	p.AutoCancel(AutoCancelPolicy{OnPush: true, Scope: "bogus"})
+}
+
+func TestNonCancelableTask(t *testing.T) {
+	p := New()
+	p.AutoCancel(AutoCancelPolicy{OnPush: true})
+	p.Task("deploy").Run("./deploy.sh").NonCancelable()
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := result["tasks"].([]interface{})[0].(map[string]interface{})
+	if task["non_cancelable"] != true {
+		t.Errorf("expected non_cancelable=true, got %v", task["non_cancelable"])
+	}
+}
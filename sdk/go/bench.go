@@ -0,0 +1,186 @@
+package sykli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"sykli.dev/go/actioncache"
+)
+
+// =============================================================================
+// CACHE BENCHMARKING
+// =============================================================================
+
+// BenchResult is one task's two-phase benchmark measurement, reported in
+// testing.B-compatible units (ns/op) so it composes with benchstat: a
+// clean phase (empty cache, everything runs) establishes NsPerOp, then an
+// incremental phase - after BenchOptions.Touch mutates one input -
+// establishes CachedNsPerOp and CacheHitRatio for whichever tasks that
+// touch didn't invalidate.
+type BenchResult struct {
+	Task             string  `json:"task"`
+	N                int     `json:"n"`
+	NsPerOp          int64   `json:"ns_per_op"`
+	CachedNsPerOp    int64   `json:"cached_ns_per_op"`
+	CacheHitRatio    float64 `json:"cache_hit_ratio"`
+	BytesTransferred int64   `json:"bytes_transferred"`
+}
+
+// BenchOptions configures Bench.
+type BenchOptions struct {
+	// N is the number of clean+incremental iterations to run, like
+	// testing.B's -count. N <= 0 is treated as 1.
+	N int
+	// Touch is the path mutated between each iteration's clean and
+	// incremental phase - the file a pipeline author is trying to decide
+	// whether Inputs() is scoped tightly around. Leaving it empty runs
+	// the incremental phase with nothing changed, so every task should
+	// come back as a cache hit.
+	Touch string
+}
+
+// Bench runs p against target BenchOptions.N times, each time as a clean
+// build against an empty actioncache.Cache followed by an incremental
+// build against that same (now warm) cache, and reports per-task timing
+// and cache effectiveness. It's the measurement half of tuning Inputs():
+// a task whose CacheHitRatio is low despite Touch pointing at an
+// unrelated file has its Inputs() glob scoped too broadly.
+//
+// BytesTransferred approximates the payload a cache hit read back by
+// summing len(Result.Output) across cached runs - this SDK's actioncache
+// doesn't track a separate transfer-size metric, and Output is the only
+// per-entry payload it has visibility into.
+func Bench(ctx context.Context, p *Pipeline, target Target, opts BenchOptions) ([]BenchResult, error) {
+	if opts.N <= 0 {
+		opts.N = 1
+	}
+
+	accum := make(map[string]*benchAccum, len(p.tasks))
+	for _, t := range p.tasks {
+		accum[t.name] = &benchAccum{}
+	}
+
+	for i := 0; i < opts.N; i++ {
+		cacheDir, err := os.MkdirTemp("", "sykli-bench-")
+		if err != nil {
+			return nil, fmt.Errorf("bench: %w", err)
+		}
+		store := actioncache.FileCache(cacheDir)
+
+		clean, err := runBenchPhase(ctx, p, target, store)
+		if err != nil {
+			os.RemoveAll(cacheDir)
+			return nil, fmt.Errorf("bench: clean phase: %w", err)
+		}
+		for name, m := range clean {
+			accum[name].clean += m.duration
+		}
+
+		if opts.Touch != "" {
+			if err := touchFile(opts.Touch); err != nil {
+				os.RemoveAll(cacheDir)
+				return nil, fmt.Errorf("bench: touch %q: %w", opts.Touch, err)
+			}
+		}
+
+		incremental, err := runBenchPhase(ctx, p, target, store)
+		os.RemoveAll(cacheDir)
+		if err != nil {
+			return nil, fmt.Errorf("bench: incremental phase: %w", err)
+		}
+		for name, m := range incremental {
+			a := accum[name]
+			a.cached += m.duration
+			if m.cached {
+				a.hits++
+				a.bytes += m.bytes
+			}
+		}
+	}
+
+	results := make([]BenchResult, 0, len(p.tasks))
+	for _, t := range p.tasks {
+		a := accum[t.name]
+		results = append(results, BenchResult{
+			Task:             t.name,
+			N:                opts.N,
+			NsPerOp:          a.clean.Nanoseconds() / int64(opts.N),
+			CachedNsPerOp:    a.cached.Nanoseconds() / int64(opts.N),
+			CacheHitRatio:    float64(a.hits) / float64(opts.N),
+			BytesTransferred: a.bytes,
+		})
+	}
+	return results, nil
+}
+
+// benchAccum totals one task's measurements across Bench's N iterations.
+type benchAccum struct {
+	clean  time.Duration
+	cached time.Duration
+	hits   int
+	bytes  int64
+}
+
+// benchTaskResult is one task's outcome from a single runBenchPhase call.
+type benchTaskResult struct {
+	duration time.Duration
+	cached   bool
+	bytes    int64
+}
+
+// runBenchPhase runs p against target once, using store as the
+// Executor's registered cache, and returns each task's wall-clock
+// duration and whether it was served from cache. Every task's start
+// defaults to the phase's own start time so a cache hit - which skips
+// Executor's OnTaskStart, since it never reaches target.RunTask - still
+// gets a reasonable duration instead of zero.
+func runBenchPhase(ctx context.Context, p *Pipeline, target Target, store actioncache.Cache) (map[string]benchTaskResult, error) {
+	phaseStart := time.Now()
+
+	var mu sync.Mutex
+	starts := make(map[string]time.Time, len(p.tasks))
+	for _, t := range p.tasks {
+		starts[t.name] = phaseStart
+	}
+	results := make(map[string]benchTaskResult, len(p.tasks))
+
+	exec := NewExecutor().RegisterCache(store).WithHooks(ExecutorHooks{
+		OnTaskStart: func(name string) {
+			mu.Lock()
+			starts[name] = time.Now()
+			mu.Unlock()
+		},
+		OnTaskEnd: func(name string, result Result) {
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = benchTaskResult{
+				duration: time.Since(starts[name]),
+				cached:   result.Cached,
+				bytes:    int64(len(result.Output)),
+			}
+		},
+	})
+
+	if _, err := exec.Run(ctx, p, target); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// touchFile mutates path's content so its next cache.ExpandInputs hash
+// differs from whatever a prior phase cached, simulating the one-line
+// edit a pipeline author makes between a clean and an incremental build.
+// Updating only the file's mtime wouldn't do this - sykli's cache keys
+// are content hashes, not mtime-based like make's.
+func touchFile(path string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString("\n")
+	return err
+}
@@ -0,0 +1,102 @@
+package sykli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// countingTarget is a minimal Target that counts how many times each
+// named task actually ran, for Bench's cache-hit-ratio assertions.
+type countingTarget struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (c *countingTarget) RunTask(ctx context.Context, task TaskSpec) Result {
+	c.mu.Lock()
+	if c.calls == nil {
+		c.calls = make(map[string]int)
+	}
+	c.calls[task.Name]++
+	c.mu.Unlock()
+	return Result{Success: true, Output: "ran " + task.Name}
+}
+
+func TestBenchReportsOneResultPerTask(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+	p.Task("test").Run("go test ./...").After("build")
+
+	results, err := Bench(context.Background(), p, &countingTarget{}, BenchOptions{N: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected one BenchResult per task, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.N != 2 {
+			t.Errorf("task %q: expected N=2, got %d", r.Task, r.N)
+		}
+	}
+}
+
+func TestBenchIncrementalPhaseHitsCacheWhenNothingTouched(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+
+	target := &countingTarget{}
+	results, err := Bench(context.Background(), p, target, BenchOptions{N: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build := findBenchResult(t, results, "build")
+	if build.CacheHitRatio != 1 {
+		t.Errorf("expected every incremental phase to hit cache with nothing touched, got ratio %v", build.CacheHitRatio)
+	}
+	// 3 clean-phase runs + 0 incremental-phase runs (all hits).
+	if target.calls["build"] != 3 {
+		t.Errorf("expected build to actually run only on the 3 clean phases, ran %d times", target.calls["build"])
+	}
+}
+
+func TestBenchTouchInvalidatesCacheForDependentTask(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(inputFile, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := New()
+	p.Task("build").Run("go build ./...").Inputs(inputFile)
+
+	target := &countingTarget{}
+	results, err := Bench(context.Background(), p, target, BenchOptions{N: 2, Touch: inputFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build := findBenchResult(t, results, "build")
+	if build.CacheHitRatio != 0 {
+		t.Errorf("expected touching build's own input to invalidate every incremental phase, got ratio %v", build.CacheHitRatio)
+	}
+	// 2 clean-phase runs + 2 incremental-phase runs (all misses after touch).
+	if target.calls["build"] != 4 {
+		t.Errorf("expected build to run on every phase, ran %d times", target.calls["build"])
+	}
+}
+
+func findBenchResult(t *testing.T, results []BenchResult, task string) BenchResult {
+	t.Helper()
+	for _, r := range results {
+		if r.Task == task {
+			return r
+		}
+	}
+	t.Fatalf("no BenchResult for task %q", task)
+	return BenchResult{}
+}
@@ -0,0 +1,96 @@
+// Package cache computes a deterministic, content-addressed key for a
+// compiled task, so a runner can decide whether to reuse a prior run's
+// output with one comparison instead of bespoke invalidation logic -
+// mirroring the canonical-struct-hashing approach BuildKit's OCI executor
+// takes via hashstructure. Rather than pull in a reflection-based hashing
+// library, Key hashes a canonical JSON encoding of the task's inputs with
+// every map and slice pre-sorted, which gets the same stable-digest
+// result without a new dependency this SDK wouldn't otherwise need.
+// sykli.Pipeline.CacheKey is the entry point; this package holds the
+// conversion logic and the plain data types it operates over.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+)
+
+// FileHash is one input file's content-addressed identity.
+type FileHash struct {
+	Path   string `json:"path"`
+	Mode   uint32 `json:"mode"`
+	SHA256 string `json:"sha256"`
+}
+
+// EnvVar is one environment variable entry; TaskKey carries these as a
+// sorted slice rather than a map so Key's JSON encoding is deterministic.
+type EnvVar struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// MountHash is one resolved mount, keyed by the hash of whatever it
+// mounts (a directory's aggregate file hash, a cache volume's name, ...)
+// rather than the mount's own settings, so two tasks mounting the same
+// resource at different container paths still compare equal on the
+// resource's content, and differ only in Path.
+type MountHash struct {
+	Path         string `json:"path"`
+	ResourceHash string `json:"resource_hash"`
+}
+
+// TaskKey is the canonical, hashable shape of everything that determines
+// whether a task's output can be reused: its command, the container
+// image reference (ideally a resolved digest, so "latest" doesn't
+// collide across rebuilds - resolving that is a runner concern, since it
+// needs registry access this SDK doesn't have), sorted env, resolved
+// mounts, expanded input file hashes, and upstream task-input hashes.
+type TaskKey struct {
+	Command         string      `json:"command"`
+	ContainerDigest string      `json:"container_digest"`
+	Env             []EnvVar    `json:"env"`
+	Mounts          []MountHash `json:"mounts"`
+	Inputs          []FileHash  `json:"inputs"`
+	TaskInputHashes []string    `json:"task_input_hashes"`
+}
+
+// Key hashes k into a stable, hex-encoded sha256 digest prefixed
+// "sha256:" the way container image digests are written, so a cache_key
+// reads unambiguously next to ContainerDigest in logs. Env, Mounts,
+// Inputs and TaskInputHashes are sorted in place before hashing, so
+// callers don't need to pre-sort them.
+func Key(k TaskKey) (string, error) {
+	sort.Slice(k.Env, func(i, j int) bool { return k.Env[i].Key < k.Env[j].Key })
+	sort.Slice(k.Mounts, func(i, j int) bool { return k.Mounts[i].Path < k.Mounts[j].Path })
+	sort.Slice(k.Inputs, func(i, j int) bool { return k.Inputs[i].Path < k.Inputs[j].Path })
+	k.TaskInputHashes = append([]string(nil), k.TaskInputHashes...)
+	sort.Strings(k.TaskInputHashes)
+
+	b, err := json.Marshal(k)
+	if err != nil {
+		return "", fmt.Errorf("cache: marshaling task key: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// HashFile reads path and returns its FileHash entry. mode is taken from
+// the caller (rather than re-stat'd here) so ExpandInputs can pass the
+// fs.FileInfo it already has from walking/globbing.
+func HashFile(path string, mode fs.FileMode) (FileHash, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return FileHash{}, fmt.Errorf("cache: reading %q: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return FileHash{
+		Path:   path,
+		Mode:   uint32(mode.Perm()),
+		SHA256: hex.EncodeToString(sum[:]),
+	}, nil
+}
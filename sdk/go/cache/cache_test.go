@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyIsDeterministicRegardlessOfInputOrder(t *testing.T) {
+	k1 := TaskKey{
+		Command:         "go test ./...",
+		ContainerDigest: "golang:1.21",
+		Env:             []EnvVar{{Key: "B", Value: "2"}, {Key: "A", Value: "1"}},
+	}
+	k2 := TaskKey{
+		Command:         "go test ./...",
+		ContainerDigest: "golang:1.21",
+		Env:             []EnvVar{{Key: "A", Value: "1"}, {Key: "B", Value: "2"}},
+	}
+
+	h1, err := Key(k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := Key(k2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h1 != h2 {
+		t.Errorf("expected env order to not affect key, got %q != %q", h1, h2)
+	}
+}
+
+func TestKeyChangesWithCommand(t *testing.T) {
+	h1, err := Key(TaskKey{Command: "go build ./..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := Key(TaskKey{Command: "go test ./..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h1 == h2 {
+		t.Error("expected different commands to produce different keys")
+	}
+}
+
+func TestKeyHasSHA256Prefix(t *testing.T) {
+	h, err := Key(TaskKey{Command: "echo hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h) != len("sha256:")+64 || h[:7] != "sha256:" {
+		t.Errorf("expected sha256:<64 hex chars>, got %q", h)
+	}
+}
+
+func TestHashFileReflectsContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fh, err := HashFile(path, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fh.Path != path || fh.SHA256 == "" {
+		t.Errorf("unexpected FileHash: %+v", fh)
+	}
+
+	if err := os.WriteFile(path, []byte("goodbye"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fh2, _ := HashFile(path, 0o644)
+	if fh.SHA256 == fh2.SHA256 {
+		t.Error("expected hash to change when file content changes")
+	}
+}
+
+func TestExpandInputsWalksDirectories(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "b.go"), []byte("package a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	hashes, err := ExpandInputs([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 2 {
+		t.Fatalf("expected 2 files hashed, got %d: %+v", len(hashes), hashes)
+	}
+}
+
+func TestExpandInputsDedupesOverlappingPatterns(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	hashes, err := ExpandInputs([]string{filepath.Join(dir, "*.go"), filepath.Join(dir, "a.go")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 1 {
+		t.Errorf("expected overlapping patterns to dedupe to 1 file, got %d", len(hashes))
+	}
+}
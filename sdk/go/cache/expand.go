@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ExpandInputs resolves patterns (shell globs, as accepted by
+// filepath.Glob) against the filesystem and returns a FileHash for every
+// matched regular file. A pattern matching a directory is walked
+// recursively so a task that declares "src" as an input picks up every
+// file underneath it, not just the directory entry itself.
+func ExpandInputs(patterns []string) ([]FileHash, error) {
+	seen := make(map[string]bool)
+	var out []FileHash
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("cache: expanding input pattern %q: %w", pattern, err)
+		}
+		for _, match := range matches {
+			if err := addPath(match, seen, &out); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return out, nil
+}
+
+// addPath hashes path (walking it first if it's a directory), skipping
+// anything already seen so the same file reached via two patterns is
+// only hashed once.
+func addPath(path string, seen map[string]bool, out *[]FileHash) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("cache: stat %q: %w", path, err)
+	}
+
+	if !info.IsDir() {
+		if seen[path] {
+			return nil
+		}
+		seen[path] = true
+		fh, err := HashFile(path, info.Mode())
+		if err != nil {
+			return err
+		}
+		*out = append(*out, fh)
+		return nil
+	}
+
+	return filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() || seen[p] {
+			return nil
+		}
+		seen[p] = true
+		fh, err := HashFile(p, fi.Mode())
+		if err != nil {
+			return err
+		}
+		*out = append(*out, fh)
+		return nil
+	})
+}
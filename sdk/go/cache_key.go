@@ -0,0 +1,133 @@
+package sykli
+
+import (
+	"fmt"
+
+	"sykli.dev/go/cache"
+)
+
+// =============================================================================
+// CONTENT-ADDRESSED CACHE KEYS
+// =============================================================================
+
+// CacheKey computes a deterministic, content-addressed key for taskName,
+// hashing its command, container image reference, sorted env, resolved
+// mounts, expanded input file contents, and the cache keys of every task
+// it pulls an InputFrom artifact from (computed recursively, so a change
+// anywhere upstream changes every downstream key too). See the cache
+// package for exactly what goes into the hash.
+//
+// The same key is what EmitTo/buildDoc attach to each task as
+// cache_key - CacheKey is exported so a runner can ask for one task's key
+// directly, e.g. to decide whether to skip a single task without
+// re-emitting the whole pipeline.
+func (p *Pipeline) CacheKey(taskName string) (string, error) {
+	if cycle := p.detectCycle(); cycle != nil {
+		return "", fmt.Errorf("CacheKey: dependency cycle detected: %s", formatCyclePath(cycle))
+	}
+	t := p.taskByName(taskName)
+	if t == nil {
+		return "", fmt.Errorf("CacheKey: task %q not found", taskName)
+	}
+	return p.cacheKeyFor(t, make(map[string]string))
+}
+
+// taskByName returns the task named name, or nil if there is none.
+func (p *Pipeline) taskByName(name string) *Task {
+	for _, t := range p.tasks {
+		if t.name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// cacheKeyFor computes t's TaskKey and hashes it, memoizing into computed
+// so a task pulled in via InputFrom by two different downstream tasks is
+// only hashed once.
+func (p *Pipeline) cacheKeyFor(t *Task, computed map[string]string) (string, error) {
+	if k, ok := computed[t.name]; ok {
+		return k, nil
+	}
+
+	env := make([]cache.EnvVar, 0, len(t.env))
+	for k, v := range t.env {
+		env = append(env, cache.EnvVar{Key: k, Value: v})
+	}
+
+	mounts := make([]cache.MountHash, 0, len(t.mounts))
+	for _, m := range t.mounts {
+		mounts = append(mounts, cache.MountHash{
+			Path:         m.path,
+			ResourceHash: mountResourceHash(m),
+		})
+	}
+
+	patterns := append([]string(nil), t.inputs...)
+	for _, discover := range t.inputDiscoverers {
+		discovered, err := discover()
+		if err != nil {
+			return "", fmt.Errorf("task %q: discovering inputs: %w", t.name, err)
+		}
+		patterns = append(patterns, discovered...)
+	}
+
+	inputs, err := cache.ExpandInputs(patterns)
+	if err != nil {
+		return "", fmt.Errorf("task %q: %w", t.name, err)
+	}
+
+	taskInputHashes := make([]string, 0, len(t.taskInputs))
+	for _, ti := range t.taskInputs {
+		upstream := p.taskByName(ti.fromTask)
+		if upstream == nil {
+			continue
+		}
+		upstreamKey, err := p.cacheKeyFor(upstream, computed)
+		if err != nil {
+			return "", err
+		}
+		taskInputHashes = append(taskInputHashes, upstreamKey)
+	}
+
+	key, err := cache.Key(cache.TaskKey{
+		Command:         t.command,
+		ContainerDigest: t.container,
+		Env:             env,
+		Mounts:          mounts,
+		Inputs:          inputs,
+		TaskInputHashes: taskInputHashes,
+	})
+	if err != nil {
+		return "", fmt.Errorf("task %q: %w", t.name, err)
+	}
+
+	computed[t.name] = key
+	return key, nil
+}
+
+// mountResourceHash identifies what a mount actually points at, rather
+// than its mount settings, so the same resource mounted at two different
+// container paths still hashes the same underlying content: a directory
+// mount's resource hash is the sha256 of its files, a cache mount's is
+// just its name (a cache volume's content is runner-managed, not
+// something this SDK can see), and anything else falls back to its
+// resource ID.
+func mountResourceHash(m Mount) string {
+	switch m.mountType {
+	case "directory":
+		hashes, err := cache.ExpandInputs([]string{m.sourcePath})
+		if err != nil {
+			return "directory:" + m.sourcePath
+		}
+		key, err := cache.Key(cache.TaskKey{Inputs: hashes})
+		if err != nil {
+			return "directory:" + m.sourcePath
+		}
+		return key
+	case "cache":
+		return "cache:" + m.cacheName
+	default:
+		return m.mountType + ":" + m.resource
+	}
+}
@@ -0,0 +1,86 @@
+package sykli
+
+import "testing"
+
+func TestCacheKeyDeterministicForIdenticalTasks(t *testing.T) {
+	p1 := New()
+	p1.Task("build").Container("golang:1.21").Run("go build ./...").Env("CI", "true")
+	p2 := New()
+	p2.Task("build").Container("golang:1.21").Run("go build ./...").Env("CI", "true")
+
+	k1, err := p1.CacheKey("build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := p2.CacheKey("build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k1 != k2 {
+		t.Errorf("expected identical tasks to produce the same cache key, got %q != %q", k1, k2)
+	}
+}
+
+func TestCacheKeyChangesWithCommand(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+	p.Task("other").Run("go test ./...")
+
+	k1, err := p.CacheKey("build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := p.CacheKey("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k1 == k2 {
+		t.Error("expected different commands to produce different cache keys")
+	}
+}
+
+func TestCacheKeyChangesWhenUpstreamTaskInputChanges(t *testing.T) {
+	p1 := New()
+	p1.Task("build").Run("go build -o /out/app").Output("binary", "/out/app")
+	p1.Task("package").Run("./package.sh").InputFrom("build", "binary", "/app")
+
+	p2 := New()
+	p2.Task("build").Run("go build -o /out/app -ldflags=-s").Output("binary", "/out/app")
+	p2.Task("package").Run("./package.sh").InputFrom("build", "binary", "/app")
+
+	k1, err := p1.CacheKey("package")
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := p2.CacheKey("package")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k1 == k2 {
+		t.Error("expected package's cache key to change when its upstream build task's command changes")
+	}
+}
+
+func TestCacheKeyUnknownTaskErrors(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+
+	if _, err := p.CacheKey("missing"); err == nil {
+		t.Error("expected error for unknown task name")
+	}
+}
+
+func TestEmitToAttachesCacheKeyToEveryTask(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	build := findTaskByName(result, "build")
+	key, ok := build["cache_key"].(string)
+	if !ok || key == "" {
+		t.Errorf("expected non-empty cache_key on emitted task, got %+v", build)
+	}
+}
@@ -0,0 +1,103 @@
+package sykli
+
+import "testing"
+
+func TestCacheSharingDefaultOmittedFromJSON(t *testing.T) {
+	p := New()
+	cache := p.Cache("go-mod")
+	p.Task("test").Container("golang:1.21").MountCache(cache, "/go/pkg/mod").Run("go test")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mount := result["tasks"].([]interface{})[0].(map[string]interface{})["mounts"].([]interface{})[0].(map[string]interface{})
+	if _, ok := mount["sharing"]; ok {
+		t.Errorf("expected no sharing field when unset, got %v", mount["sharing"])
+	}
+}
+
+func TestCacheSharingModeSerialized(t *testing.T) {
+	p := New()
+	cache := p.Cache("go-mod").Sharing(CacheShared)
+	p.Task("test").Container("golang:1.21").MountCache(cache, "/go/pkg/mod").Run("go test")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mount := result["tasks"].([]interface{})[0].(map[string]interface{})["mounts"].([]interface{})[0].(map[string]interface{})
+	if mount["sharing"] != "shared" {
+		t.Errorf("expected sharing 'shared', got %v", mount["sharing"])
+	}
+}
+
+func TestCacheInvalidSharingPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for invalid sharing mode")
+		}
+	}()
+	p := New()
+	p.Cache("go-mod").Sharing("bogus")
+}
+
+func TestMountCacheWithOwnerAndMode(t *testing.T) {
+	p := New()
+	cache := p.Cache("go-mod")
+	p.Task("test").
+		Container("golang:1.21").
+		MountCache(cache, "/go/pkg/mod", WithOwner(1000, 1000), WithMode(0755), WithReadOnly()).
+		Run("go test")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mount := result["tasks"].([]interface{})[0].(map[string]interface{})["mounts"].([]interface{})[0].(map[string]interface{})
+	if mount["uid"] != float64(1000) || mount["gid"] != float64(1000) {
+		t.Errorf("expected uid/gid 1000, got uid=%v gid=%v", mount["uid"], mount["gid"])
+	}
+	if mount["mode"] != "0755" {
+		t.Errorf("expected mode '0755', got %v", mount["mode"])
+	}
+	if mount["readonly"] != true {
+		t.Errorf("expected readonly true, got %v", mount["readonly"])
+	}
+}
+
+func TestMountCacheInvalidModePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for invalid octal mode")
+		}
+	}()
+	p := New()
+	cache := p.Cache("go-mod")
+	p.Task("test").MountCache(cache, "/go/pkg/mod", WithMode(999))
+}
+
+func TestLockedCacheInParallelGroupFails(t *testing.T) {
+	p := New()
+	cache := p.Cache("npm").Sharing(CacheLocked)
+
+	a := p.Task("a").Container("node:20").MountCache(cache, "/npm").Run("npm ci")
+	b := p.Task("b").Container("node:20").MountCache(cache, "/npm").Run("npm ci")
+	p.Parallel("checks", a, b)
+
+	if _, err := emitJSON(p); err == nil {
+		t.Error("expected error for two locked mounts of the same cache in a parallel group")
+	}
+}
+
+func TestLockedCacheAcrossDifferentGroupsIsFine(t *testing.T) {
+	p := New()
+	cache := p.Cache("npm").Sharing(CacheLocked)
+
+	p.Task("a").Container("node:20").MountCache(cache, "/npm").Run("npm ci")
+	p.Task("b").Container("node:20").MountCache(cache, "/npm").Run("npm ci").After("a")
+
+	if _, err := emitJSON(p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package sykli
+
+// =============================================================================
+// HOST CAPABILITY GATING
+// =============================================================================
+
+// Capability describes one thing a task needs the host to provide, e.g. a
+// container runtime, CGO, or a specific GOOS/GOARCH. It's opaque data -
+// the constructor functions below (CapDocker, CapGOOS, etc.) are the
+// supported way to build one.
+type Capability struct {
+	Kind     string // "docker", "podman", "cgo", "race", "kvm", "network_egress", "goos", "goarch", "env", "binary", "custom"
+	Value    string // parameter for goos/goarch/env/binary, or the name for custom
+	ProbeCmd string // shell command for "custom"; empty otherwise
+}
+
+// CapDocker requires a working Docker daemon.
+func CapDocker() Capability { return Capability{Kind: "docker"} }
+
+// CapPodman requires a working podman installation.
+func CapPodman() Capability { return Capability{Kind: "podman"} }
+
+// CapCGO requires CGO_ENABLED=1 and a working C toolchain, mirroring
+// cmd/go's own canCgo test gate.
+func CapCGO() Capability { return Capability{Kind: "cgo"} }
+
+// CapRace requires a host the race detector runs on, mirroring cmd/go's
+// own canRace test gate.
+func CapRace() Capability { return Capability{Kind: "race"} }
+
+// CapKVM requires /dev/kvm, for tasks that boot a VM.
+func CapKVM() Capability { return Capability{Kind: "kvm"} }
+
+// CapNetworkEgress requires outbound network access.
+func CapNetworkEgress() Capability { return Capability{Kind: "network_egress"} }
+
+// CapGOOS requires the host to report the given GOOS.
+func CapGOOS(os string) Capability {
+	if os == "" {
+		log.Panic().Msg("CapGOOS: os cannot be empty")
+	}
+	return Capability{Kind: "goos", Value: os}
+}
+
+// CapGOARCH requires the host to report the given GOARCH.
+func CapGOARCH(arch string) Capability {
+	if arch == "" {
+		log.Panic().Msg("CapGOARCH: arch cannot be empty")
+	}
+	return Capability{Kind: "goarch", Value: arch}
+}
+
+// CapEnv requires the named environment variable to be set and non-empty.
+func CapEnv(name string) Capability {
+	if name == "" {
+		log.Panic().Msg("CapEnv: name cannot be empty")
+	}
+	return Capability{Kind: "env", Value: name}
+}
+
+// CapBinary requires the named executable to be resolvable on PATH.
+func CapBinary(name string) Capability {
+	if name == "" {
+		log.Panic().Msg("CapBinary: name cannot be empty")
+	}
+	return Capability{Kind: "binary", Value: name}
+}
+
+// CapCustom requires probeCmd to exit zero. name is a short, stable
+// identifier for the capability that shows up in skip reasons - it isn't
+// run, only probeCmd is.
+func CapCustom(name, probeCmd string) Capability {
+	if name == "" || probeCmd == "" {
+		log.Panic().Str("name", name).Msg("CapCustom: name and probeCmd cannot be empty")
+	}
+	return Capability{Kind: "custom", Value: name, ProbeCmd: probeCmd}
+}
+
+// Requires declares the capabilities this task needs the host to provide.
+// The runner probes each capability once per pipeline execution, caches
+// the result, and skips (not fails) any task whose host doesn't have it,
+// recording a machine-readable reason in the run report. Multiple calls
+// union their capabilities.
+func (t *Task) Requires(caps ...Capability) *Task {
+	t.requires = append(t.requires, caps...)
+	return t
+}
@@ -0,0 +1,91 @@
+package sykli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRequiresEmitted(t *testing.T) {
+	p := New()
+	p.Task("build").Run("docker build .").Requires(CapDocker(), CapGOOS("linux"))
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqs := findTaskByName(result, "build")["requires"].([]interface{})
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 capabilities, got %d", len(reqs))
+	}
+	docker := reqs[0].(map[string]interface{})
+	if docker["kind"] != "docker" {
+		t.Errorf("expected first capability kind=docker, got %v", docker)
+	}
+	goos := reqs[1].(map[string]interface{})
+	if goos["kind"] != "goos" || goos["value"] != "linux" {
+		t.Errorf("expected second capability kind=goos value=linux, got %v", goos)
+	}
+}
+
+func TestRequiresOmittedWhenUnset(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := findTaskByName(result, "build")["requires"]; ok {
+		t.Error("expected no requires field when Requires was never called")
+	}
+}
+
+func TestCapCustomEmitsProbeCmd(t *testing.T) {
+	p := New()
+	p.Task("gpu-test").Run("./gpu-test").Requires(CapCustom("nvidia-gpu", "nvidia-smi"))
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cap := findTaskByName(result, "gpu-test")["requires"].([]interface{})[0].(map[string]interface{})
+	if cap["kind"] != "custom" || cap["value"] != "nvidia-gpu" || cap["probe_cmd"] != "nvidia-smi" {
+		t.Errorf("unexpected custom capability: %+v", cap)
+	}
+}
+
+func TestCapGOOSEmptyPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty GOOS")
+		}
+	}()
+	CapGOOS("")
+}
+
+func TestCapCustomEmptyProbeCmdPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty probeCmd")
+		}
+	}()
+	CapCustom("nvidia-gpu", "")
+}
+
+func TestRequiresRoundTripsThroughParse(t *testing.T) {
+	p := New()
+	p.Task("build").Run("docker build .").Requires(CapDocker(), CapEnv("SSH_AUTH_SOCK"))
+
+	var buf bytes.Buffer
+	if err := p.EmitTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(&buf, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := parsed.tasks[0]
+	if len(task.requires) != 2 || task.requires[0].Kind != "docker" || task.requires[1].Kind != "env" || task.requires[1].Value != "SSH_AUTH_SOCK" {
+		t.Errorf("unexpected parsed capabilities: %+v", task.requires)
+	}
+}
@@ -0,0 +1,257 @@
+package sykli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sykli.dev/go/internal/compile"
+)
+
+// =============================================================================
+// COMPILE: MATRIX EXPANSION
+// =============================================================================
+
+// ExpandMatrix returns a new Pipeline where every task with matrix
+// dimensions is replaced by one concrete task per combination (excludes
+// pruned, includes appended, same as expandMatrixCombinations), each named
+// "<task>[k1=v1,k2=v2]" with keys sorted for a stable, deterministic name.
+// Each expansion gets the combination's values injected as env vars, the
+// same place Matrix's own values end up once a runner picks a cell. Any
+// task that depended on the original task now depends on every one of its
+// expansions, so the DAG's ordering is preserved. p itself is left
+// unmodified; Finally tasks never have a matrix (see Task.Matrix) and are
+// copied across as-is.
+func ExpandMatrix(p *Pipeline) (*Pipeline, error) {
+	out := New()
+	expansions := make(map[string][]string, len(p.tasks)) // original task name -> expanded names
+
+	for _, t := range p.tasks {
+		if len(t.matrix) == 0 {
+			cloneMatrixTask(out, t, t.name, nil)
+			expansions[t.name] = []string{t.name}
+			continue
+		}
+		combos := expandMatrixCombinations(t)
+		if len(combos) == 0 {
+			return nil, fmt.Errorf("task %q: matrix produced no combinations (did an Exclude drop every one?)", t.name)
+		}
+		names := make([]string, len(combos))
+		for i, combo := range combos {
+			names[i] = matrixTaskName(t.name, combo)
+			cloneMatrixTask(out, t, names[i], combo)
+		}
+		expansions[t.name] = names
+	}
+
+	outByName := make(map[string]*Task, len(out.tasks))
+	for _, t := range out.tasks {
+		outByName[t.name] = t
+	}
+	for _, t := range p.tasks {
+		for _, name := range expansions[t.name] {
+			nt := outByName[name]
+			nt.dependsOn = nil
+			for _, dep := range t.dependsOn {
+				nt.dependsOn = append(nt.dependsOn, expansions[dep]...)
+			}
+		}
+	}
+
+	for _, t := range p.finallyTasks {
+		cloneFinallyTask(out, t)
+	}
+
+	return out, nil
+}
+
+// matrixTaskName returns the deterministic expanded name for one matrix
+// combination: keys sorted so the same combination always names the same
+// task, regardless of Go map iteration order.
+func matrixTaskName(taskName string, combo map[string]string) string {
+	keys := make([]string, 0, len(combo))
+	for k := range combo {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + combo[k]
+	}
+	return fmt.Sprintf("%s[%s]", taskName, strings.Join(parts, ","))
+}
+
+// cloneMatrixTask copies t's settings onto a new task named name in out,
+// injecting combo's values as env vars and dropping the matrix dimensions
+// themselves, since name already encodes the single combination it
+// represents. dependsOn is left for the caller to rewrite once every
+// task's expansions are known.
+func cloneMatrixTask(out *Pipeline, t *Task, name string, combo map[string]string) *Task {
+	nt := &Task{
+		pipeline:      out,
+		name:          name,
+		command:       t.command,
+		container:     t.container,
+		workdir:       t.workdir,
+		env:           make(map[string]string, len(t.env)+len(combo)),
+		mounts:        append([]Mount(nil), t.mounts...),
+		inputs:        append([]string(nil), t.inputs...),
+		taskInputs:    append([]TaskInput(nil), t.taskInputs...),
+		outputs:       make(map[string]string, len(t.outputs)),
+		when:          t.when,
+		whenAST:       t.whenAST,
+		secrets:       append([]string(nil), t.secrets...),
+		services:      append([]Service(nil), t.services...),
+		retry:         t.retry,
+		timeout:       t.timeout,
+		nonCancelable: t.nonCancelable,
+		runsOn:        append([]string(nil), t.runsOn...),
+		references:    append([]TaskReference(nil), t.references...),
+	}
+	for k, v := range t.env {
+		nt.env[k] = v
+	}
+	for k, v := range combo {
+		nt.env[k] = v
+	}
+	for k, v := range t.outputs {
+		nt.outputs[k] = v
+	}
+	out.tasks = append(out.tasks, nt)
+	return nt
+}
+
+// cloneFinallyTask copies a finally task across unmodified - Finally tasks
+// never carry a matrix, so there's nothing to expand.
+func cloneFinallyTask(out *Pipeline, t *Task) *Task {
+	nt := &Task{
+		pipeline:      out,
+		name:          t.name,
+		command:       t.command,
+		container:     t.container,
+		workdir:       t.workdir,
+		env:           make(map[string]string, len(t.env)),
+		mounts:        append([]Mount(nil), t.mounts...),
+		inputs:        append([]string(nil), t.inputs...),
+		taskInputs:    append([]TaskInput(nil), t.taskInputs...),
+		outputs:       make(map[string]string, len(t.outputs)),
+		dependsOn:     append([]string(nil), t.dependsOn...),
+		when:          t.when,
+		whenAST:       t.whenAST,
+		secrets:       append([]string(nil), t.secrets...),
+		services:      append([]Service(nil), t.services...),
+		retry:         t.retry,
+		timeout:       t.timeout,
+		nonCancelable: t.nonCancelable,
+		runsOn:        append([]string(nil), t.runsOn...),
+		references:    append([]TaskReference(nil), t.references...),
+		isFinally:     true,
+	}
+	for k, v := range t.env {
+		nt.env[k] = v
+	}
+	for k, v := range t.outputs {
+		nt.outputs[k] = v
+	}
+	out.finallyTasks = append(out.finallyTasks, nt)
+	return nt
+}
+
+// hasMatrixTasks reports whether any task in p declares matrix dimensions,
+// gating EmitTo's expanded_tasks field.
+func hasMatrixTasks(p *Pipeline) bool {
+	for _, t := range p.tasks {
+		if len(t.matrix) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// =============================================================================
+// COMPILE: FLAT, VALIDATED DAG
+// =============================================================================
+
+// CompiledTask is one task in a CompiledPipeline: the flat, fully-resolved
+// form Compile() produces. Chain, Parallel, AfterGroup, InputFrom, and
+// Template.From already lower into plain DependsOn edges and task fields as
+// soon as they're called on the builder - Compile's own job is expanding
+// any Matrix dimensions into concrete tasks and then validating the
+// result, not a separate lowering pass over those constructs.
+type CompiledTask struct {
+	Name      string
+	DependsOn []string
+}
+
+// CompiledPipeline is the validated, flattened intermediate representation
+// Pipeline.Compile produces: every task concrete (no unexpanded matrices),
+// every dependency a real task name, no duplicate names, and no cycles.
+type CompiledPipeline struct {
+	tasks  []CompiledTask
+	levels [][]string
+	doc    *jsonPipeline
+}
+
+// Compile validates p and returns its flat dependency graph: Matrix
+// dimensions are expanded into concrete tasks (the same expansion
+// ExpandMatrix performs), then the result is run through the same
+// duplicate-name, unknown-dependency, and cycle checks EmitTo itself uses
+// (via buildDoc) before being handed to the internal/compile package for
+// topological leveling. Unlike Emit/EmitTo, which serialize the pipeline
+// as the user wrote it (with expanded_tasks alongside the unexpanded
+// matrix tasks, for a runner that wants to look at both), Compile returns
+// only the flattened, ready-to-schedule form.
+func (p *Pipeline) Compile() (*CompiledPipeline, error) {
+	expanded := p
+	if hasMatrixTasks(p) {
+		var err error
+		expanded, err = ExpandMatrix(p)
+		if err != nil {
+			return nil, fmt.Errorf("compile: %w", err)
+		}
+	}
+
+	doc, err := expanded.buildDoc()
+	if err != nil {
+		return nil, fmt.Errorf("compile: %w", err)
+	}
+
+	nodes := make([]compile.Node, len(expanded.tasks))
+	for i, t := range expanded.tasks {
+		nodes[i] = compile.Node{Name: t.name, DependsOn: t.dependsOn}
+	}
+	graph, err := compile.New(nodes)
+	if err != nil {
+		return nil, fmt.Errorf("compile: %w", err)
+	}
+
+	tasks := make([]CompiledTask, len(expanded.tasks))
+	for i, t := range expanded.tasks {
+		tasks[i] = CompiledTask{Name: t.name, DependsOn: append([]string(nil), t.dependsOn...)}
+	}
+
+	return &CompiledPipeline{tasks: tasks, levels: graph.Levels(), doc: doc}, nil
+}
+
+// Tasks returns every task in the compiled pipeline, in declaration order
+// (with matrix expansions in place of the task that declared them).
+func (c *CompiledPipeline) Tasks() []CompiledTask {
+	return c.tasks
+}
+
+// TopoLevels returns the pipeline's parallel wavefronts: level 0 holds
+// every task with no dependencies, level 1 holds every task whose
+// dependencies are all in level 0, and so on. Tasks in the same level have
+// no edge between them and can run concurrently.
+func (c *CompiledPipeline) TopoLevels() [][]string {
+	return c.levels
+}
+
+// MarshalJSON encodes the compiled pipeline in the same wire format
+// EmitTo writes, so a runner that only links CompiledPipeline (not the
+// Task/Pipeline builder) can still decode it with the existing schema.
+func (c *CompiledPipeline) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.doc)
+}
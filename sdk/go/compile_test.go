@@ -0,0 +1,188 @@
+package sykli
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+)
+
+func TestExpandMatrixNamesAndEnv(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test ./...").Matrix("os", "linux", "darwin").Matrix("go", "1.20", "1.21")
+
+	out, err := ExpandMatrix(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.tasks) != 4 {
+		t.Fatalf("expected 4 expanded tasks, got %d", len(out.tasks))
+	}
+
+	var names []string
+	for _, nt := range out.tasks {
+		names = append(names, nt.name)
+		if nt.env["os"] == "" || nt.env["go"] == "" {
+			t.Errorf("expected matrix values injected as env vars, got %+v", nt.env)
+		}
+	}
+	sort.Strings(names)
+	want := "test[go=1.20,os=darwin]"
+	if names[0] != want {
+		t.Errorf("expected deterministic sorted-key name %q, got %q", want, names[0])
+	}
+}
+
+func TestExpandMatrixPropagatesDependencies(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test ./...").Matrix("os", "linux", "darwin")
+	p.Task("publish").Run("./publish.sh").After("test")
+
+	out, err := ExpandMatrix(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var publish *Task
+	for _, nt := range out.tasks {
+		if nt.name == "publish" {
+			publish = nt
+		}
+	}
+	if publish == nil {
+		t.Fatal("expected publish task to survive expansion")
+	}
+	if len(publish.dependsOn) != 2 {
+		t.Fatalf("expected publish to depend on both expanded test tasks, got %v", publish.dependsOn)
+	}
+}
+
+func TestExpandMatrixLeavesNonMatrixTasksUntouched(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+
+	out, err := ExpandMatrix(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.tasks) != 1 || out.tasks[0].name != "build" {
+		t.Errorf("expected build task unchanged, got %+v", out.tasks)
+	}
+}
+
+func TestExpandMatrixAppliesExcludes(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test ./...").
+		Matrix("os", "linux", "darwin").
+		Matrix("go", "1.20", "1.21").
+		MatrixExclude(map[string]string{"os": "darwin", "go": "1.20"})
+
+	out, err := ExpandMatrix(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.tasks) != 3 {
+		t.Fatalf("expected 3 expanded tasks after exclude, got %d", len(out.tasks))
+	}
+}
+
+func TestEmitToIncludesExpandedTasks(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test ./...").Matrix("os", "linux", "darwin")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expanded, ok := result["expanded_tasks"].([]interface{})
+	if !ok || len(expanded) != 2 {
+		t.Fatalf("expected 2 expanded_tasks, got %v", result["expanded_tasks"])
+	}
+}
+
+func TestEmitToOmitsExpandedTasksWithoutMatrix(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := result["expanded_tasks"]; ok {
+		t.Errorf("expected expanded_tasks to be omitted, got %v", result["expanded_tasks"])
+	}
+}
+
+func TestCompileProducesTopoLevels(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test ./...")
+	p.Task("lint").Run("go vet ./...")
+	p.Task("build").Run("go build ./...").After("test", "lint")
+	p.Task("publish").Run("./publish.sh").After("build")
+
+	cp, err := p.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	levels := cp.TopoLevels()
+	if len(levels) != 3 {
+		t.Fatalf("expected 3 levels, got %d: %v", len(levels), levels)
+	}
+	if len(levels[0]) != 2 {
+		t.Errorf("expected 2 tasks in level 0, got %v", levels[0])
+	}
+	if len(cp.Tasks()) != 4 {
+		t.Errorf("expected 4 compiled tasks, got %d", len(cp.Tasks()))
+	}
+}
+
+func TestCompileExpandsMatrix(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test ./...").Matrix("os", "linux", "darwin")
+	p.Task("publish").Run("./publish.sh").After("test")
+
+	cp, err := p.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cp.Tasks()) != 3 {
+		t.Fatalf("expected 2 expanded test tasks + publish, got %d", len(cp.Tasks()))
+	}
+	for _, ct := range cp.Tasks() {
+		if ct.Name == "publish" && len(ct.DependsOn) != 2 {
+			t.Errorf("expected publish to depend on both expanded test tasks, got %v", ct.DependsOn)
+		}
+	}
+}
+
+func TestCompileRejectsCycle(t *testing.T) {
+	p := New()
+	p.Task("a").Run("echo a")
+	p.Task("b").Run("echo b").After("a")
+	p.tasks[0].dependsOn = []string{"b"}
+
+	if _, err := p.Compile(); err == nil {
+		t.Fatal("expected error for dependency cycle")
+	}
+}
+
+func TestCompileMarshalsToWireFormat(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+
+	cp, err := p.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(cp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatal(err)
+	}
+	tasks, ok := doc["tasks"].([]interface{})
+	if !ok || len(tasks) != 1 {
+		t.Fatalf("expected 1 task in marshaled output, got %v", doc["tasks"])
+	}
+}
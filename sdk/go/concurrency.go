@@ -0,0 +1,95 @@
+package sykli
+
+import "regexp"
+
+// =============================================================================
+// CONCURRENCY GROUPS
+// =============================================================================
+
+// ConcurrencyPolicy controls how runs that land in the same named group
+// supersede or queue behind each other.
+type ConcurrencyPolicy struct {
+	// Group is the key runs are grouped by. It may reference ${branch},
+	// ${event}, or ${pr}; like a When condition's own branch/event/tag
+	// variables, the runner interpolates these from the triggering
+	// webhook at run time - the SDK only validates the token names.
+	Group string
+
+	// CancelInProgress marks older pending/running runs in the same
+	// group cancelable once a newer run enters it.
+	CancelInProgress bool
+
+	// QueueDepth caps how many runs may be queued in the same group at
+	// once; 0 means unlimited.
+	QueueDepth int
+
+	// WaitForPrevious serializes runs in the same group: a new run waits
+	// for the previous one to finish instead of running alongside it.
+	WaitForPrevious bool
+}
+
+// ConcurrencyOption configures a ConcurrencyPolicy. See CancelInProgress,
+// QueueDepth, and WaitForPrevious.
+type ConcurrencyOption func(*ConcurrencyPolicy)
+
+// CancelInProgress cancels older pending/running runs in the same group
+// when a newer run enters it.
+func CancelInProgress() ConcurrencyOption {
+	return func(p *ConcurrencyPolicy) { p.CancelInProgress = true }
+}
+
+// QueueDepth caps how many runs may be queued in the same group at once.
+func QueueDepth(n int) ConcurrencyOption {
+	return func(p *ConcurrencyPolicy) { p.QueueDepth = n }
+}
+
+// WaitForPrevious serializes runs in the same group, one at a time.
+func WaitForPrevious() ConcurrencyOption {
+	return func(p *ConcurrencyPolicy) { p.WaitForPrevious = true }
+}
+
+// concurrencyGroupVarPattern matches a ${name} interpolation token in a
+// Concurrency group key.
+var concurrencyGroupVarPattern = regexp.MustCompile(`\$\{([a-zA-Z_]+)\}`)
+
+// concurrencyGroupVars are the only interpolation tokens the runner knows
+// how to resolve in a concurrency group key.
+var concurrencyGroupVars = map[string]bool{
+	"branch": true,
+	"event":  true,
+	"pr":     true,
+}
+
+// Concurrency registers a pipeline-wide concurrency group policy, emitted
+// as a top-level `concurrency` object. This only compiles the declared
+// intent into the pipeline JSON - interpolating group and deciding what
+// "superseded" means for CancelInProgress, QueueDepth, and
+// WaitForPrevious is the runner's job.
+//
+// Example:
+//
+//	p.Concurrency("deploy-${branch}", sykli.CancelInProgress())
+func (p *Pipeline) Concurrency(group string, opts ...ConcurrencyOption) *Pipeline {
+	if group == "" {
+		log.Panic().Msg("Concurrency: group cannot be empty")
+	}
+	for _, match := range concurrencyGroupVarPattern.FindAllStringSubmatch(group, -1) {
+		if !concurrencyGroupVars[match[1]] {
+			log.Panic().Str("group", group).Str("var", match[1]).Msg("Concurrency: unknown interpolation variable")
+		}
+	}
+
+	policy := ConcurrencyPolicy{Group: group}
+	for _, opt := range opts {
+		opt(&policy)
+	}
+	if policy.QueueDepth < 0 {
+		log.Panic().Int("queue_depth", policy.QueueDepth).Msg("Concurrency: queue depth cannot be negative")
+	}
+	if policy.WaitForPrevious && policy.CancelInProgress {
+		log.Panic().Msg("Concurrency: WaitForPrevious and CancelInProgress are mutually exclusive")
+	}
+
+	p.concurrency = &policy
+	return p
+}
@@ -0,0 +1,93 @@
+package sykli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestConcurrencyEmitted(t *testing.T) {
+	p := New()
+	p.Concurrency("deploy-${branch}", CancelInProgress(), QueueDepth(3))
+	p.Task("deploy").Run("./deploy.sh")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := result["concurrency"].(map[string]interface{})
+	if c["group"] != "deploy-${branch}" || c["cancel_in_progress"] != true || c["queue_depth"] != float64(3) {
+		t.Errorf("unexpected concurrency: %+v", c)
+	}
+}
+
+func TestConcurrencyOmittedWhenUnset(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test ./...")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := result["concurrency"]; ok {
+		t.Error("expected no concurrency field when Concurrency was never called")
+	}
+}
+
+func TestConcurrencyEmptyGroupPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty group")
+		}
+	}()
+	p := New()
+	p.Concurrency("")
+}
+
+func TestConcurrencyUnknownVariablePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for unknown interpolation variable")
+		}
+	}()
+	p := New()
+	p.Concurrency("deploy-${bogus}")
+}
+
+func TestConcurrencyWaitForPreviousAndCancelInProgressPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic when combining WaitForPrevious and CancelInProgress")
+		}
+	}()
+	p := New()
+	p.Concurrency("deploy", WaitForPrevious(), CancelInProgress())
+}
+
+func TestConcurrencyNegativeQueueDepthPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for negative queue depth")
+		}
+	}()
+	p := New()
+	p.Concurrency("deploy", QueueDepth(-1))
+}
+
+func TestConcurrencyRoundTripsThroughParse(t *testing.T) {
+	p := New()
+	p.Concurrency("deploy-${branch}", WaitForPrevious())
+	p.Task("deploy").Run("./deploy.sh")
+
+	var buf bytes.Buffer
+	if err := p.EmitTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := Parse(&buf, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.concurrency == nil || parsed.concurrency.Group != "deploy-${branch}" || !parsed.concurrency.WaitForPrevious {
+		t.Errorf("unexpected parsed concurrency: %+v", parsed.concurrency)
+	}
+}
@@ -0,0 +1,186 @@
+package sykli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// =============================================================================
+// INPUT DISCOVERY
+// =============================================================================
+
+// GoPackage returns an InputsFrom discoverer that shells `go list -deps
+// -json` to compute pkgDir's actual transitive .go file set, mirroring
+// what cmd/go/internal/work does to decide whether a build is stale -
+// so a task only invalidates when a file it actually depends on changes,
+// instead of every .go file Inputs("**/*.go") would match.
+func GoPackage(pkgDir string) func() ([]string, error) {
+	return func() ([]string, error) {
+		return goListFiles(pkgDir, false)
+	}
+}
+
+// GoTestPackage is GoPackage plus pkgDir's own _test.go files, for a
+// `go test ./pkg` task - go test only needs the target package's test
+// files, not its dependencies', so those are added for pkgDir alone.
+func GoTestPackage(pkgDir string) func() ([]string, error) {
+	return func() ([]string, error) {
+		return goListFiles(pkgDir, true)
+	}
+}
+
+// goListPackage is the subset of `go list -json` fields this package
+// reads; the real output has many more.
+type goListPackage struct {
+	Dir          string
+	Standard     bool
+	GoFiles      []string
+	TestGoFiles  []string
+	XTestGoFiles []string
+}
+
+func goListFiles(pkgDir string, includeTests bool) ([]string, error) {
+	absDir, err := filepath.Abs(pkgDir)
+	if err != nil {
+		return nil, fmt.Errorf("sykli: resolving %q: %w", pkgDir, err)
+	}
+
+	cmd := exec.Command("go", "list", "-deps", "-json", ".")
+	cmd.Dir = absDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("sykli: go list -deps %q: %w", pkgDir, err)
+	}
+
+	var files []string
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for dec.More() {
+		var pkg goListPackage
+		if err := dec.Decode(&pkg); err != nil {
+			return nil, fmt.Errorf("sykli: decoding go list output for %q: %w", pkgDir, err)
+		}
+		if pkg.Standard {
+			continue
+		}
+		for _, f := range pkg.GoFiles {
+			files = append(files, filepath.Join(pkg.Dir, f))
+		}
+		if includeTests && pkg.Dir == absDir {
+			for _, f := range append(pkg.TestGoFiles, pkg.XTestGoFiles...) {
+				files = append(files, filepath.Join(pkg.Dir, f))
+			}
+		}
+	}
+	return files, nil
+}
+
+// PythonModule returns an InputsFrom discoverer that parses name's
+// import statements and walks the modules they resolve to, transitively,
+// collecting every .py file reached. Resolution is deliberately
+// simplified - only modules found as a sibling "<name>.py" file or
+// "<name>/__init__.py" package next to the entry module are followed;
+// stdlib and third-party imports (which don't resolve that way) are
+// left alone, matching what a task actually needs to rebuild rather
+// than every installed package.
+func PythonModule(name string) func() ([]string, error) {
+	return func() ([]string, error) {
+		return pythonModuleFiles(name)
+	}
+}
+
+var pythonImportPattern = regexp.MustCompile(`(?m)^\s*(?:import\s+([\w.]+)|from\s+([\w.]+)\s+import\s+([\w, ]+))`)
+
+// importCandidates expands one pythonImportPattern match into groups of
+// module names it might refer to, each group tried in most-specific-first
+// order until one resolves: "import X" yields a single group [X], while
+// "from X import Y, Z" yields one group per name - [X.Y, X] and [X.Z, X] -
+// since Y/Z may each be a submodule of package X, or just a name defined
+// inside X's __init__.py.
+func importCandidates(match []string) [][]string {
+	if imported := match[1]; imported != "" {
+		return [][]string{{imported}}
+	}
+
+	pkg := match[2]
+	var groups [][]string
+	for _, name := range strings.Split(match[3], ",") {
+		fields := strings.Fields(name) // first word, dropping any "as alias"
+		if len(fields) > 0 {
+			groups = append(groups, []string{pkg + "." + fields[0], pkg})
+		}
+	}
+	return groups
+}
+
+func pythonModuleFiles(name string) ([]string, error) {
+	entry, dir, err := resolvePythonModule(name, ".")
+	if err != nil {
+		return nil, fmt.Errorf("sykli: resolving python module %q: %w", name, err)
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+
+	var walk func(path, fromDir string) error
+	walk = func(path, fromDir string) error {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("sykli: resolving %q: %w", path, err)
+		}
+		if seen[abs] {
+			return nil
+		}
+		seen[abs] = true
+		files = append(files, path)
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("sykli: reading %q: %w", path, err)
+		}
+		for _, match := range pythonImportPattern.FindAllStringSubmatch(string(data), -1) {
+			for _, group := range importCandidates(match) {
+				for _, candidate := range group {
+					impPath, impDir, err := resolvePythonModule(candidate, fromDir)
+					if err != nil {
+						continue // not a local module - try the next candidate in this group
+					}
+					if err := walk(impPath, impDir); err != nil {
+						return err
+					}
+					break // resolved - don't also walk this group's less-specific fallback
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := walk(entry, dir); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// resolvePythonModule resolves dotted module name name to a .py file
+// relative to dir, as either "<name>.py" or "<name>/__init__.py", and
+// returns that file's own directory for resolving its imports in turn.
+func resolvePythonModule(name, dir string) (file, moduleDir string, err error) {
+	rel := strings.ReplaceAll(name, ".", string(filepath.Separator))
+
+	asFile := filepath.Join(dir, rel+".py")
+	if info, statErr := os.Stat(asFile); statErr == nil && !info.IsDir() {
+		return asFile, filepath.Dir(asFile), nil
+	}
+
+	asPackage := filepath.Join(dir, rel, "__init__.py")
+	if _, statErr := os.Stat(asPackage); statErr == nil {
+		return asPackage, filepath.Dir(asPackage), nil
+	}
+
+	return "", "", fmt.Errorf("%q not found relative to %q", name, dir)
+}
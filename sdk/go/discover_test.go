@@ -0,0 +1,149 @@
+package sykli
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGoPackageCollectsNonStdlibDeps(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/fixture\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "helper", "helper.go"), "package helper\n\nfunc Help() string { return \"ok\" }\n")
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nimport \"example.com/fixture/helper\"\n\nfunc main() { _ = helper.Help() }\n")
+
+	files, err := GoPackage(dir)()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{filepath.Join(dir, "main.go"), filepath.Join(dir, "helper", "helper.go")}
+	assertSameFileSet(t, files, want)
+}
+
+func TestGoTestPackageIncludesOwnTestFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/fixture\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "add.go"), "package fixture\n\nfunc Add(a, b int) int { return a + b }\n")
+	writeFile(t, filepath.Join(dir, "add_test.go"), "package fixture\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {\n\tif Add(1, 2) != 3 {\n\t\tt.Fail()\n\t}\n}\n")
+
+	files, err := GoTestPackage(dir)()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{filepath.Join(dir, "add.go"), filepath.Join(dir, "add_test.go")}
+	assertSameFileSet(t, files, want)
+}
+
+func TestGoPackagePlainBuildOmitsTestFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/fixture\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "add.go"), "package fixture\n\nfunc Add(a, b int) int { return a + b }\n")
+	writeFile(t, filepath.Join(dir, "add_test.go"), "package fixture\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {\n\tif Add(1, 2) != 3 {\n\t\tt.Fail()\n\t}\n}\n")
+
+	files, err := GoPackage(dir)()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{filepath.Join(dir, "add.go")}
+	assertSameFileSet(t, files, want)
+}
+
+func TestPythonModuleWalksLocalImports(t *testing.T) {
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+
+	writeFile(t, filepath.Join(dir, "app.py"), "import helper\nfrom pkg import util\nimport os\n\nprint(helper.greet())\n")
+	writeFile(t, filepath.Join(dir, "helper.py"), "def greet():\n    return 'hi'\n")
+	writeFile(t, filepath.Join(dir, "pkg", "__init__.py"), "")
+	writeFile(t, filepath.Join(dir, "pkg", "util.py"), "VALUE = 1\n")
+
+	files, err := PythonModule("app")()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"app.py", "helper.py", filepath.Join("pkg", "util.py")}
+	assertSameFileSet(t, files, want)
+}
+
+func TestPythonModuleMissingErrors(t *testing.T) {
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+
+	if _, err := PythonModule("missing")(); err == nil {
+		t.Error("expected error for a module with no matching file")
+	}
+}
+
+func TestInputsFromFeedsCacheKey(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "dep.txt")
+	writeFile(t, file, "v1")
+
+	newPipeline := func() *Pipeline {
+		p := New()
+		p.Task("build").Run("./build.sh").InputsFrom(func() ([]string, error) {
+			return []string{file}, nil
+		})
+		return p
+	}
+
+	k1, err := newPipeline().CacheKey("build")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, file, "v2")
+	k2, err := newPipeline().CacheKey("build")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if k1 == k2 {
+		t.Error("expected cache key to change when an InputsFrom-discovered file changes")
+	}
+}
+
+func assertSameFileSet(t *testing.T, got []string, want []string) {
+	t.Helper()
+	gotSorted := append([]string(nil), got...)
+	wantSorted := append([]string(nil), want...)
+	sort.Strings(gotSorted)
+	sort.Strings(wantSorted)
+	if len(gotSorted) != len(wantSorted) {
+		t.Fatalf("got %v, want %v", gotSorted, wantSorted)
+	}
+	for i := range gotSorted {
+		if filepath.Clean(gotSorted[i]) != filepath.Clean(wantSorted[i]) {
+			t.Fatalf("got %v, want %v", gotSorted, wantSorted)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package sykli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEmitFormatJSONPrettyIsIndented(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+
+	var buf bytes.Buffer
+	p.EmitFormat(FormatJSONPretty)
+	if err := p.EmitTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\n  ") {
+		t.Errorf("expected indented JSON, got %s", buf.String())
+	}
+}
+
+func TestEmitFormatYAMLRoundTripsSameData(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...").Env("CGO_ENABLED", "0")
+
+	var jsonBuf, yamlBuf bytes.Buffer
+	if err := p.EmitTo(&jsonBuf); err != nil {
+		t.Fatal(err)
+	}
+	p.EmitFormat(FormatYAML)
+	if err := p.EmitTo(&yamlBuf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(yamlBuf.String(), "CGO_ENABLED") {
+		t.Errorf("expected YAML output to contain task env, got %s", yamlBuf.String())
+	}
+	if strings.Contains(yamlBuf.String(), "{") {
+		t.Errorf("expected YAML output, got what looks like JSON: %s", yamlBuf.String())
+	}
+}
+
+func TestEmitVersionPinsSchemaVersion(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+	p.EmitVersion("v2")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result["version"] != "2" {
+		t.Errorf("expected pinned version 2, got %v", result["version"])
+	}
+}
+
+func TestEmitVersionInvalidPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for unsupported EmitVersion")
+		}
+	}()
+	New().EmitVersion("3")
+}
+
+func TestParseEmitFormatUnknownPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for unknown --format value")
+		}
+	}()
+	parseEmitFormat("toml")
+}
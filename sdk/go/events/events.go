@@ -0,0 +1,180 @@
+// Package events defines a structured event stream for pipeline
+// execution: an Event type, a pluggable EventSink output, and an
+// EventBus that fans events out to every configured sink while keeping a
+// ring buffer so late subscribers can Replay what they missed. It's
+// modelled loosely on Kubernetes' events API.
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Well-known Reason values. sykli.Executor emits TaskScheduled,
+// TaskStarted, TaskSucceeded, TaskFailed, and GateWaiting directly, since
+// those map onto its own scheduling loop. The rest name events that
+// belong to parts of the system this SDK doesn't implement yet (a retry
+// loop, a gate-approval flow, service/artifact/secret resolution) - a
+// runner or target is free to emit them on the same bus once it has
+// something to report.
+const (
+	ReasonTaskScheduled     = "TaskScheduled"
+	ReasonTaskStarted       = "TaskStarted"
+	ReasonTaskSucceeded     = "TaskSucceeded"
+	ReasonTaskFailed        = "TaskFailed"
+	ReasonTaskRetrying      = "TaskRetrying"
+	ReasonGateWaiting       = "GateWaiting"
+	ReasonGateApproved      = "GateApproved"
+	ReasonServiceStarted    = "ServiceStarted"
+	ReasonArtifactPublished = "ArtifactPublished"
+	ReasonSecretResolved    = "SecretResolved"
+)
+
+// Event types, matching the "Normal"/"Warning" convention from
+// Kubernetes events.
+const (
+	TypeNormal  = "Normal"
+	TypeWarning = "Warning"
+)
+
+// Event is one entry in a pipeline's event stream.
+type Event struct {
+	Type       string // TypeNormal or TypeWarning
+	Reason     string // one of the Reason* constants, or a caller-defined one
+	TaskName   string
+	Timestamp  time.Time
+	Message    string
+	Attributes map[string]string
+}
+
+// EventSink receives every event an EventBus fans out. Implement this to
+// add a new output - a stdout renderer and a JSON-lines writer ship in
+// this package; an OTLP exporter or anything else with third-party
+// dependencies belongs in the caller, built the same way.
+type EventSink interface {
+	Emit(e Event)
+}
+
+// EventFilter narrows a Watch subscription or a Replay call. Zero-value
+// fields match everything; set fields are combined with AND.
+type EventFilter struct {
+	TaskName string
+	Reason   string
+	Type     string
+}
+
+func (f EventFilter) matches(e Event) bool {
+	if f.TaskName != "" && f.TaskName != e.TaskName {
+		return false
+	}
+	if f.Reason != "" && f.Reason != e.Reason {
+		return false
+	}
+	if f.Type != "" && f.Type != e.Type {
+		return false
+	}
+	return true
+}
+
+// EventBus fans events out to every registered sink and keeps the most
+// recent events in a ring buffer so late Watch subscribers can Replay
+// what they missed.
+type EventBus struct {
+	mu       sync.Mutex
+	sinks    []EventSink
+	ring     []Event
+	ringSize int
+	subs     []*subscription
+}
+
+type subscription struct {
+	filter EventFilter
+	ch     chan Event
+}
+
+// NewEventBus creates an EventBus that retains the most recent ringSize
+// events for Replay. ringSize <= 0 disables the ring buffer: Replay
+// always returns nothing.
+func NewEventBus(ringSize int) *EventBus {
+	return &EventBus{ringSize: ringSize}
+}
+
+// AddSink registers sink to receive every event emitted from now on.
+func (b *EventBus) AddSink(sink EventSink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sinks = append(b.sinks, sink)
+}
+
+// Emit fans e out to every sink, appends it to the ring buffer, and
+// delivers it to every Watch subscriber whose filter matches. Emit never
+// blocks on a slow subscriber - a subscriber whose channel buffer is full
+// simply misses the event rather than stalling the pipeline.
+func (b *EventBus) Emit(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, sink := range b.sinks {
+		sink.Emit(e)
+	}
+
+	if b.ringSize > 0 {
+		b.ring = append(b.ring, e)
+		if len(b.ring) > b.ringSize {
+			b.ring = b.ring[len(b.ring)-b.ringSize:]
+		}
+	}
+
+	for _, sub := range b.subs {
+		if !sub.filter.matches(e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+		}
+	}
+}
+
+// Watch returns a channel of future events matching filter. The channel
+// is closed once ctx is done. Watch only delivers events emitted after
+// the call; pair it with Replay to also see history.
+func (b *EventBus) Watch(ctx context.Context, filter EventFilter) <-chan Event {
+	ch := make(chan Event, 64)
+	sub := &subscription{filter: filter, ch: ch}
+
+	b.mu.Lock()
+	b.subs = append(b.subs, sub)
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, s := range b.subs {
+			if s == sub {
+				b.subs = append(b.subs[:i], b.subs[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Replay returns every ring-buffered event at or after from, oldest
+// first.
+func (b *EventBus) Replay(from time.Time) []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var out []Event
+	for _, e := range b.ring {
+		if !e.Timestamp.Before(from) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingSink struct{ events []Event }
+
+func (r *recordingSink) Emit(e Event) { r.events = append(r.events, e) }
+
+func TestEmitFansOutToEverySink(t *testing.T) {
+	bus := NewEventBus(0)
+	a, b := &recordingSink{}, &recordingSink{}
+	bus.AddSink(a)
+	bus.AddSink(b)
+
+	bus.Emit(Event{Reason: ReasonTaskStarted, TaskName: "build"})
+
+	if len(a.events) != 1 || len(b.events) != 1 {
+		t.Fatalf("expected both sinks to receive the event, got %d and %d", len(a.events), len(b.events))
+	}
+}
+
+func TestWatchDeliversMatchingEvents(t *testing.T) {
+	bus := NewEventBus(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := bus.Watch(ctx, EventFilter{TaskName: "build"})
+
+	bus.Emit(Event{Reason: ReasonTaskStarted, TaskName: "test"}) // filtered out
+	bus.Emit(Event{Reason: ReasonTaskStarted, TaskName: "build"})
+
+	select {
+	case e := <-ch:
+		if e.TaskName != "build" {
+			t.Errorf("expected build event, got %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for matching event")
+	}
+}
+
+func TestWatchChannelClosesWhenContextDone(t *testing.T) {
+	bus := NewEventBus(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := bus.Watch(ctx, EventFilter{})
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("expected channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for channel to close")
+	}
+}
+
+func TestReplayReturnsRingBufferedEventsFromTime(t *testing.T) {
+	bus := NewEventBus(10)
+	old := Event{Reason: ReasonTaskStarted, TaskName: "a", Timestamp: time.Unix(100, 0)}
+	recent := Event{Reason: ReasonTaskSucceeded, TaskName: "a", Timestamp: time.Unix(200, 0)}
+	bus.Emit(old)
+	bus.Emit(recent)
+
+	replayed := bus.Replay(time.Unix(150, 0))
+	if len(replayed) != 1 || replayed[0].Reason != ReasonTaskSucceeded {
+		t.Fatalf("expected only the recent event, got %+v", replayed)
+	}
+}
+
+func TestReplayRingBufferEvictsOldestBeyondSize(t *testing.T) {
+	bus := NewEventBus(2)
+	for i := 0; i < 3; i++ {
+		bus.Emit(Event{TaskName: "t", Timestamp: time.Unix(int64(i), 0)})
+	}
+	replayed := bus.Replay(time.Unix(0, 0))
+	if len(replayed) != 2 {
+		t.Fatalf("expected ring buffer capped at 2, got %d", len(replayed))
+	}
+}
+
+func TestStdoutSinkWritesOneLine(t *testing.T) {
+	var buf bytes.Buffer
+	sink := StdoutSink{Out: &buf}
+	sink.Emit(Event{Type: TypeNormal, Reason: ReasonTaskStarted, TaskName: "build", Message: "running"})
+
+	if !strings.Contains(buf.String(), "TaskStarted") || !strings.Contains(buf.String(), "build") {
+		t.Errorf("expected rendered line to contain reason and task name, got %q", buf.String())
+	}
+}
+
+func TestJSONLinesSinkWritesValidJSON(t *testing.T) {
+	var buf bytes.Buffer
+	sink := JSONLinesSink{Out: &buf}
+	sink.Emit(Event{Reason: ReasonTaskFailed, TaskName: "build"})
+
+	if !strings.HasSuffix(buf.String(), "\n") {
+		t.Error("expected a trailing newline")
+	}
+	if !strings.Contains(buf.String(), `"TaskFailed"`) {
+		t.Errorf("expected JSON to contain the reason, got %q", buf.String())
+	}
+}
@@ -0,0 +1,35 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// StdoutSink renders events as single human-readable lines, e.g. for a
+// runner's own console output.
+type StdoutSink struct {
+	Out io.Writer
+}
+
+// Emit writes e as one line to s.Out.
+func (s StdoutSink) Emit(e Event) {
+	fmt.Fprintf(s.Out, "%s %-7s %-17s %s: %s\n",
+		e.Timestamp.Format("15:04:05"), e.Type, e.Reason, e.TaskName, e.Message)
+}
+
+// JSONLinesSink writes each event as one JSON object per line, suitable
+// for a log file tools like `sykli inspect` can tail or replay.
+type JSONLinesSink struct {
+	Out io.Writer
+}
+
+// Emit writes e to s.Out as a single line of JSON. Marshaling errors are
+// dropped rather than propagated, matching Emit's no-error signature.
+func (s JSONLinesSink) Emit(e Event) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	s.Out.Write(append(b, '\n'))
+}
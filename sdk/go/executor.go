@@ -0,0 +1,412 @@
+package sykli
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"sykli.dev/go/actioncache"
+	"sykli.dev/go/events"
+)
+
+// CacheMode controls how an Executor uses its registered actioncache.Cache,
+// mirroring the readonly/readwrite/off modes a CLI's --cache flag would
+// set - this SDK has no CLI of its own, so WithCacheMode is the thing such
+// a flag would call.
+type CacheMode int
+
+const (
+	// CacheReadWrite looks up a hit before running a task and stores the
+	// outcome after, the default when a Cache is registered.
+	CacheReadWrite CacheMode = iota
+	// CacheReadOnly looks up a hit but never stores new outcomes.
+	CacheReadOnly
+	// CacheOff ignores a registered Cache entirely.
+	CacheOff
+)
+
+// =============================================================================
+// EXECUTOR
+// =============================================================================
+
+// ExecutorHooks lets callers observe task lifecycle events without wrapping
+// every Target. Any hook left nil is simply skipped.
+type ExecutorHooks struct {
+	// OnTaskStart fires right before a task is handed to the Target.
+	OnTaskStart func(taskName string)
+
+	// OnTaskEnd fires once a task's Result is known.
+	OnTaskEnd func(taskName string, result Result)
+
+	// OnGateWait fires the first time a task is found blocked on
+	// dependencies that haven't finished yet. There's no separate Gate
+	// node in this SDK today - waiting on DependsOn is the only wait
+	// state an Executor has, so this is also where a future gate-style
+	// construct would report in.
+	OnGateWait func(taskName string)
+}
+
+// Executor drives a Pipeline's tasks against a Target concurrently,
+// respecting DependsOn (which is what Parallel/AfterGroup compile down
+// to - see Pipeline.Parallel), a bounded global worker pool, and optional
+// per-target concurrency limits.
+//
+// Example:
+//
+//	exec := sykli.NewExecutor().WithConcurrency(4)
+//	results, err := exec.Run(ctx, pipeline, target)
+type Executor struct {
+	concurrency       int
+	targetConcurrency map[string]int
+	hooks             ExecutorHooks
+	events            *events.EventBus
+	cache             actioncache.Cache
+	cacheMode         CacheMode
+	taskEvents        chan TaskEvent
+}
+
+// NewExecutor creates an Executor with no concurrency cap and no hooks.
+func NewExecutor() *Executor {
+	return &Executor{}
+}
+
+// Events returns a channel of TaskEvent that Run sends one entry to per
+// task as its cache decision is made - "skipped" or "uncached" for a
+// Skip/NoCache task, otherwise "hit" or "miss" - and closes once Run
+// returns. Call Events before Run; like Watch's subscriber channels, it
+// never blocks Run - an entry is dropped rather than stalling the
+// pipeline if the channel's buffer is full and nothing is reading it.
+func (e *Executor) Events() <-chan TaskEvent {
+	if e.taskEvents == nil {
+		e.taskEvents = make(chan TaskEvent, 64)
+	}
+	return e.taskEvents
+}
+
+// emitTaskEvent is a nil-safe convenience wrapper, mirroring emit, that
+// never blocks a slow or absent Events() reader.
+func (e *Executor) emitTaskEvent(ev TaskEvent) {
+	if e.taskEvents == nil {
+		return
+	}
+	select {
+	case e.taskEvents <- ev:
+	default:
+	}
+}
+
+// WithConcurrency caps how many tasks run at once across every target.
+// n <= 0 means unlimited.
+func (e *Executor) WithConcurrency(n int) *Executor {
+	e.concurrency = n
+	return e
+}
+
+// WithTargetConcurrency caps how many tasks run at once per Task.Target
+// value. A task with no Target set is keyed under "".
+func (e *Executor) WithTargetConcurrency(limits map[string]int) *Executor {
+	e.targetConcurrency = limits
+	return e
+}
+
+// WithHooks attaches lifecycle callbacks.
+func (e *Executor) WithHooks(hooks ExecutorHooks) *Executor {
+	e.hooks = hooks
+	return e
+}
+
+// WithEvents attaches an events.EventBus. Run emits TaskScheduled,
+// TaskStarted, TaskSucceeded/TaskFailed, and GateWaiting onto it at the
+// same points ExecutorHooks fires - use whichever fits, or both.
+func (e *Executor) WithEvents(bus *events.EventBus) *Executor {
+	e.events = bus
+	return e
+}
+
+// RegisterCache attaches a remote action cache: Run looks up each task's
+// cache_key (see Pipeline.CacheKey) against it before handing the task to
+// target, and stores the outcome after, skipping RunTask entirely on a
+// hit. WithCacheMode controls whether Run reads, writes, both, or ignores
+// the registered cache.
+func (e *Executor) RegisterCache(c actioncache.Cache) *Executor {
+	e.cache = c
+	return e
+}
+
+// WithCacheMode sets how Run uses a registered cache. The default,
+// CacheReadWrite, applies even if WithCacheMode is never called.
+func (e *Executor) WithCacheMode(mode CacheMode) *Executor {
+	e.cacheMode = mode
+	return e
+}
+
+// emit is a nil-safe convenience wrapper around e.events.Emit.
+func (e *Executor) emit(typ, reason, taskName, message string) {
+	if e.events == nil {
+		return
+	}
+	e.events.Emit(events.Event{
+		Type:      typ,
+		Reason:    reason,
+		TaskName:  taskName,
+		Timestamp: time.Now(),
+		Message:   message,
+	})
+}
+
+// Run executes every task in p against target, honoring DependsOn order
+// plus the Executor's concurrency limits, and returns each task's Result
+// keyed by task name.
+//
+// If a task's Result has Success == false and Criticality == "high",
+// Run cancels ctx for every task that hasn't started yet - a cooperative
+// shutdown, not an abrupt one: tasks already running are always awaited
+// before Run returns.
+func (e *Executor) Run(ctx context.Context, p *Pipeline, target Target) (map[string]Result, error) {
+	if cycle := p.detectCycle(); cycle != nil {
+		return nil, fmt.Errorf("executor: dependency cycle detected: %s", formatCyclePath(cycle))
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	global := newSemaphore(e.concurrency)
+	perTarget := make(map[string]*semaphore, len(e.targetConcurrency))
+	for name, n := range e.targetConcurrency {
+		perTarget[name] = newSemaphore(n)
+	}
+
+	actionIDs := make(map[string]string, len(p.tasks))
+	if e.cache != nil && e.cacheMode != CacheOff {
+		computed := make(map[string]string, len(p.tasks))
+		for _, t := range p.tasks {
+			if t.noCacheReason != "" {
+				continue // NoCache: never assign an actionID, so runOne always executes it
+			}
+			if key, err := p.cacheKeyFor(t, computed); err == nil {
+				actionIDs[t.name] = key
+			}
+		}
+	}
+
+	mu := &sync.Mutex{}
+	cond := sync.NewCond(mu)
+	results := make(map[string]Result, len(p.tasks))
+	started := make(map[string]bool, len(p.tasks))
+	finished := make(map[string]bool, len(p.tasks))
+	gateNotified := make(map[string]bool, len(p.tasks))
+	var wg sync.WaitGroup
+
+	mu.Lock()
+	for len(finished) < len(p.tasks) && runCtx.Err() == nil {
+		progressed := false
+		for _, t := range p.tasks {
+			if started[t.name] {
+				continue
+			}
+			if !allDone(t.dependsOn, finished) {
+				if !gateNotified[t.name] {
+					gateNotified[t.name] = true
+					if e.hooks.OnGateWait != nil {
+						e.hooks.OnGateWait(t.name)
+					}
+					e.emit(events.TypeNormal, events.ReasonGateWaiting, t.name, "waiting on dependencies")
+				}
+				continue
+			}
+			started[t.name] = true
+			progressed = true
+			e.emit(events.TypeNormal, events.ReasonTaskScheduled, t.name, "dependencies satisfied, dispatching")
+
+			wg.Add(1)
+			task := t
+			go func() {
+				defer wg.Done()
+				result := e.runOne(runCtx, task, target, global, perTarget, actionIDs[task.name])
+
+				mu.Lock()
+				results[task.name] = result
+				finished[task.name] = true
+				if !result.Success && result.Criticality == "high" {
+					cancel()
+				}
+				cond.Broadcast()
+				mu.Unlock()
+			}()
+		}
+		if !progressed && len(finished) < len(p.tasks) {
+			cond.Wait()
+		}
+	}
+	mu.Unlock()
+
+	wg.Wait()
+	if e.taskEvents != nil {
+		close(e.taskEvents)
+	}
+	return results, runCtx.Err()
+}
+
+// runOne runs a single task through target, blocking on the global and
+// per-target semaphores until a slot is free. actionID is t's cache_key,
+// or "" if the Executor has no cache registered (or couldn't hash t's
+// inputs) - see Run's actionIDs map.
+func (e *Executor) runOne(ctx context.Context, t *Task, target Target, global *semaphore, perTarget map[string]*semaphore, actionID string) Result {
+	if t.skipReason != "" {
+		result := Result{Success: true, Output: "skipped: " + t.skipReason}
+		if e.hooks.OnTaskEnd != nil {
+			e.hooks.OnTaskEnd(t.name, result)
+		}
+		e.emit(events.TypeNormal, events.ReasonTaskSucceeded, t.name, result.Output)
+		e.emitTaskEvent(TaskEvent{Name: t.name, Decision: "skipped", Reason: t.skipReason})
+		return result
+	}
+
+	if !global.acquire(ctx) {
+		return Result{Success: false, Error: ctx.Err()}
+	}
+	defer global.release()
+
+	if sem, ok := perTarget[t.targetName]; ok {
+		if !sem.acquire(ctx) {
+			return Result{Success: false, Error: ctx.Err()}
+		}
+		defer sem.release()
+	}
+
+	if ctx.Err() != nil {
+		return Result{Success: false, Error: ctx.Err()}
+	}
+
+	if actionID != "" && e.cache != nil && e.cacheMode != CacheOff {
+		if entry, ok, err := e.cache.Get(ctx, actionID); err == nil && ok {
+			result := Result{Success: entry.Success, ExitCode: entry.ExitCode, Output: entry.Output, Cached: true}
+			if e.hooks.OnTaskEnd != nil {
+				e.hooks.OnTaskEnd(t.name, result)
+			}
+			e.emit(events.TypeNormal, events.ReasonTaskSucceeded, t.name, "cache hit")
+			e.emitTaskEvent(TaskEvent{Name: t.name, Decision: "hit", Reason: "cache hit", ActionKey: actionID, InputDigest: actionID})
+			return result
+		}
+	}
+
+	if e.hooks.OnTaskStart != nil {
+		e.hooks.OnTaskStart(t.name)
+	}
+	e.emit(events.TypeNormal, events.ReasonTaskStarted, t.name, "running")
+
+	result := target.RunTask(ctx, taskSpecFor(t))
+
+	if actionID != "" && e.cache != nil && e.cacheMode == CacheReadWrite {
+		entry := actioncache.Entry{Success: result.Success, ExitCode: result.ExitCode, Output: result.Output, CreatedAt: time.Now()}
+		if err := e.cache.Put(ctx, actionID, entry); err != nil {
+			log.Debug().Str("task", t.name).Err(err).Msg("actioncache: storing result failed")
+		}
+	}
+
+	if e.hooks.OnTaskEnd != nil {
+		e.hooks.OnTaskEnd(t.name, result)
+	}
+	if result.Success {
+		e.emit(events.TypeNormal, events.ReasonTaskSucceeded, t.name, "completed")
+	} else {
+		e.emit(events.TypeWarning, events.ReasonTaskFailed, t.name, resultFailureMessage(result))
+	}
+	e.emitTaskEvent(taskEventFor(t, actionID, e.cache != nil && e.cacheMode != CacheOff))
+
+	return result
+}
+
+// taskEventFor reports why a task that reached target.RunTask wasn't
+// served from cache: it never had an actionID (NoCache, or no cache
+// registered/enabled), or it did but nothing was stored under it yet.
+func taskEventFor(t *Task, actionID string, cacheEnabled bool) TaskEvent {
+	ev := TaskEvent{Name: t.name, Decision: "miss", ActionKey: actionID, InputDigest: actionID}
+	switch {
+	case t.noCacheReason != "":
+		ev.Decision = "uncached"
+		ev.Reason = t.noCacheReason
+	case !cacheEnabled:
+		ev.Reason = "no cache registered"
+	case actionID == "":
+		ev.Reason = "inputs could not be hashed"
+	default:
+		ev.Reason = "no prior cache entry"
+	}
+	return ev
+}
+
+// resultFailureMessage renders a short human-readable reason for a
+// failed Result's TaskFailed event.
+func resultFailureMessage(r Result) string {
+	if r.Error != nil {
+		return r.Error.Error()
+	}
+	return "task failed"
+}
+
+// allDone reports whether every name in deps is marked done.
+func allDone(deps []string, done map[string]bool) bool {
+	for _, dep := range deps {
+		if !done[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// taskSpecFor converts t into the plain-data TaskSpec a Target consumes.
+// Mounts are left for the caller to resolve - turning a Mount into a
+// MountSpec requires a live Volume from Target.CreateVolume, which is a
+// target-specific concern the Executor doesn't orchestrate on its own.
+func taskSpecFor(t *Task) TaskSpec {
+	spec := TaskSpec{
+		Name:      t.name,
+		Command:   t.command,
+		Image:     t.container,
+		Workdir:   t.workdir,
+		Env:       t.env,
+		Timeout:   t.timeout,
+		DependsOn: t.dependsOn,
+		K8s:       t.k8sOptions,
+	}
+	for _, svc := range t.services {
+		spec.Services = append(spec.Services, ServiceSpec{Name: svc.name, Image: svc.image})
+	}
+	return spec
+}
+
+// semaphore is a simple counting semaphore built on a buffered channel.
+// A limit <= 0 means unlimited: acquire always succeeds immediately.
+type semaphore struct {
+	slots chan struct{}
+}
+
+func newSemaphore(limit int) *semaphore {
+	if limit <= 0 {
+		return &semaphore{}
+	}
+	return &semaphore{slots: make(chan struct{}, limit)}
+}
+
+// acquire blocks until a slot is free or ctx is done, returning false in
+// the latter case.
+func (s *semaphore) acquire(ctx context.Context) bool {
+	if s.slots == nil {
+		return true
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *semaphore) release() {
+	if s.slots == nil {
+		return
+	}
+	<-s.slots
+}
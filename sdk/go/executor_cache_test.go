@@ -0,0 +1,114 @@
+package sykli
+
+import (
+	"context"
+	"testing"
+
+	"sykli.dev/go/actioncache"
+)
+
+// memCache is a minimal in-memory actioncache.Cache for Executor tests.
+type memCache struct {
+	entries map[string]actioncache.Entry
+}
+
+func newMemCache() *memCache {
+	return &memCache{entries: make(map[string]actioncache.Entry)}
+}
+
+func (m *memCache) Get(ctx context.Context, actionID string) (actioncache.Entry, bool, error) {
+	e, ok := m.entries[actionID]
+	return e, ok, nil
+}
+
+func (m *memCache) Put(ctx context.Context, actionID string, entry actioncache.Entry) error {
+	m.entries[actionID] = entry
+	return nil
+}
+
+func TestExecutorCacheHitSkipsRunTask(t *testing.T) {
+	p := New()
+	p.Task("a").Run("echo a")
+
+	key, err := p.CacheKey("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newMemCache()
+	c.entries[key] = actioncache.Entry{Success: true, Output: "cached output"}
+
+	ft := &fakeTarget{}
+	results, err := NewExecutor().RegisterCache(c).Run(context.Background(), p, ft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ft.maxSeen != 0 {
+		t.Fatalf("expected RunTask never called, fakeTarget saw %d", ft.maxSeen)
+	}
+	if r := results["a"]; !r.Cached || !r.Success || r.Output != "cached output" {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+}
+
+func TestExecutorCacheMissStoresResult(t *testing.T) {
+	p := New()
+	p.Task("a").Run("echo a")
+
+	c := newMemCache()
+	ft := &fakeTarget{}
+	results, err := NewExecutor().RegisterCache(c).Run(context.Background(), p, ft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ft.maxSeen != 1 {
+		t.Fatalf("expected RunTask called once, fakeTarget saw %d", ft.maxSeen)
+	}
+	if r := results["a"]; r.Cached || !r.Success {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+	if len(c.entries) != 1 {
+		t.Fatalf("expected the miss to store an entry, got %d", len(c.entries))
+	}
+}
+
+func TestExecutorReadOnlyModeNeverStores(t *testing.T) {
+	p := New()
+	p.Task("a").Run("echo a")
+
+	c := newMemCache()
+	ft := &fakeTarget{}
+	_, err := NewExecutor().RegisterCache(c).WithCacheMode(CacheReadOnly).Run(context.Background(), p, ft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ft.maxSeen != 1 {
+		t.Fatalf("expected RunTask called once, fakeTarget saw %d", ft.maxSeen)
+	}
+	if len(c.entries) != 0 {
+		t.Fatalf("expected CacheReadOnly to never store, got %d entries", len(c.entries))
+	}
+}
+
+func TestExecutorCacheOffIgnoresRegisteredCache(t *testing.T) {
+	p := New()
+	p.Task("a").Run("echo a")
+
+	key, err := p.CacheKey("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newMemCache()
+	c.entries[key] = actioncache.Entry{Success: true, Output: "cached output"}
+
+	ft := &fakeTarget{}
+	results, err := NewExecutor().RegisterCache(c).WithCacheMode(CacheOff).Run(context.Background(), p, ft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ft.maxSeen != 1 {
+		t.Fatalf("expected RunTask called despite a cache hit available, fakeTarget saw %d", ft.maxSeen)
+	}
+	if results["a"].Cached {
+		t.Fatalf("expected CacheOff to never report a cache hit, got %+v", results["a"])
+	}
+}
@@ -0,0 +1,169 @@
+package sykli
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sykli.dev/go/events"
+)
+
+// fakeTarget is a minimal Target that tracks how many tasks are running
+// concurrently and can be told to fail specific tasks with a Criticality.
+type fakeTarget struct {
+	running int32
+	maxSeen int32
+	delay   time.Duration
+	fail    map[string]string // task name -> criticality
+}
+
+func (f *fakeTarget) RunTask(ctx context.Context, task TaskSpec) Result {
+	n := atomic.AddInt32(&f.running, 1)
+	for {
+		m := atomic.LoadInt32(&f.maxSeen)
+		if n <= m || atomic.CompareAndSwapInt32(&f.maxSeen, m, n) {
+			break
+		}
+	}
+	time.Sleep(f.delay)
+	atomic.AddInt32(&f.running, -1)
+	if crit, ok := f.fail[task.Name]; ok {
+		return Result{Success: false, Criticality: crit}
+	}
+	return Result{Success: true}
+}
+
+func TestExecutorRunsEveryTask(t *testing.T) {
+	p := New()
+	p.Task("a").Run("echo a")
+	p.Task("b").Run("echo b").After("a")
+
+	results, err := NewExecutor().Run(context.Background(), p, &fakeTarget{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || !results["a"].Success || !results["b"].Success {
+		t.Fatalf("expected both tasks to succeed, got %+v", results)
+	}
+}
+
+func TestExecutorRespectsDependsOnOrder(t *testing.T) {
+	p := New()
+	p.Task("b").Run("echo b").After("a")
+	p.Task("a").Run("echo a")
+
+	var order []string
+	hooks := ExecutorHooks{OnTaskEnd: func(name string, r Result) { order = append(order, name) }}
+	_, err := NewExecutor().WithHooks(hooks).Run(context.Background(), p, &fakeTarget{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(order) != 2 || order[0] != "a" || order[1] != "b" {
+		t.Fatalf("expected a before b, got %v", order)
+	}
+}
+
+func TestExecutorConcurrencyIsCapped(t *testing.T) {
+	p := New()
+	for i := 0; i < 8; i++ {
+		p.Task(string(rune('a' + i))).Run("sleep")
+	}
+	ft := &fakeTarget{delay: 20 * time.Millisecond}
+
+	_, err := NewExecutor().WithConcurrency(3).Run(context.Background(), p, ft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ft.maxSeen > 3 {
+		t.Fatalf("expected at most 3 tasks running at once, saw %d", ft.maxSeen)
+	}
+}
+
+func TestExecutorTargetConcurrencyIsCapped(t *testing.T) {
+	p := New()
+	for i := 0; i < 6; i++ {
+		p.Task(string(rune('a' + i))).Run("sleep").Target("k8s")
+	}
+	ft := &fakeTarget{delay: 20 * time.Millisecond}
+
+	_, err := NewExecutor().WithTargetConcurrency(map[string]int{"k8s": 2}).Run(context.Background(), p, ft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ft.maxSeen > 2 {
+		t.Fatalf("expected at most 2 tasks running at once for target k8s, saw %d", ft.maxSeen)
+	}
+}
+
+func TestExecutorHighCriticalityFailureStopsUnstartedTasks(t *testing.T) {
+	p := New()
+	p.Task("fail").Run("exit 1")
+	p.Task("downstream").Run("echo never").After("fail")
+
+	ft := &fakeTarget{fail: map[string]string{"fail": "high"}}
+	results, err := NewExecutor().Run(context.Background(), p, ft)
+	if err == nil {
+		t.Fatal("expected Run to report cancellation")
+	}
+	if _, ran := results["fail"]; !ran {
+		t.Error("expected fail to have run")
+	}
+	if _, ran := results["downstream"]; ran {
+		t.Error("expected downstream to be skipped after a high-criticality failure")
+	}
+}
+
+func TestExecutorOnGateWaitFiresForBlockedTasks(t *testing.T) {
+	p := New()
+	p.Task("a").Run("echo a")
+	p.Task("b").Run("echo b").After("a")
+
+	var waited []string
+	hooks := ExecutorHooks{OnGateWait: func(name string) { waited = append(waited, name) }}
+	ft := &fakeTarget{delay: 10 * time.Millisecond}
+	_, err := NewExecutor().WithHooks(hooks).Run(context.Background(), p, ft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(waited) != 1 || waited[0] != "b" {
+		t.Fatalf("expected only b to wait on a gate, got %v", waited)
+	}
+}
+
+func TestExecutorEmitsLifecycleEvents(t *testing.T) {
+	p := New()
+	p.Task("a").Run("echo a")
+	p.Task("b").Run("echo b").After("a")
+
+	bus := events.NewEventBus(50)
+	_, err := NewExecutor().WithEvents(bus).Run(context.Background(), p, &fakeTarget{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+	for _, e := range bus.Replay(time.Time{}) {
+		seen[e.Reason] = true
+	}
+	for _, reason := range []string{
+		events.ReasonTaskScheduled,
+		events.ReasonGateWaiting,
+		events.ReasonTaskStarted,
+		events.ReasonTaskSucceeded,
+	} {
+		if !seen[reason] {
+			t.Errorf("expected reason %q to be emitted", reason)
+		}
+	}
+}
+
+func TestExecutorDetectsCycles(t *testing.T) {
+	p := New()
+	p.Task("a").Run("echo a").After("b")
+	p.Task("b").Run("echo b").After("a")
+
+	if _, err := NewExecutor().Run(context.Background(), p, &fakeTarget{}); err == nil {
+		t.Error("expected cycle detection error")
+	}
+}
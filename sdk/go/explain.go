@@ -0,0 +1,134 @@
+package sykli
+
+import (
+	"fmt"
+	"sort"
+
+	"sykli.dev/go/cache"
+)
+
+// =============================================================================
+// CACHE-DECISION OBSERVABILITY
+// =============================================================================
+
+// TaskEvent is a structured record of why a task ran, was skipped, or was
+// served from cache - the unit both Executor.Events and Pipeline.Explain
+// report in, mirroring what Bazel's --explain and Go's build cache
+// diagnostics surface: not just a pass/fail, but the specific decision
+// and, where known, which input caused it.
+type TaskEvent struct {
+	Name     string `json:"name"`
+	Decision string `json:"decision"` // "hit", "miss", "skipped", or "uncached"
+	Reason   string `json:"reason"`
+
+	// ActionKey and InputDigest are both the task's cache_key (see
+	// Pipeline.CacheKey) - ActionKey names which cache entry was or
+	// wasn't found, InputDigest is the same value under the name a
+	// --why report would compare across runs. They're carried as two
+	// fields because a future cache backend that separates the two
+	// could populate them independently; today's FileCache/HTTPCache
+	// don't, so they're always equal.
+	ActionKey   string `json:"action_key,omitempty"`
+	InputDigest string `json:"input_digest,omitempty"`
+
+	// ChangedFiles is only populated by Explain, which has a prior
+	// digest snapshot to diff against - a live Executor.Events stream
+	// has no such history, so it always leaves this nil.
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	// CachedFrom names where a hit was served from, when a Cache
+	// implementation reports that; neither FileCache nor HTTPCache do
+	// today, so this is always empty until one does.
+	CachedFrom string `json:"cached_from,omitempty"`
+}
+
+// InputDigests returns taskName's current per-file content hash, the
+// same file set cacheKeyFor folds into the task's cache_key, but broken
+// out per path instead of collapsed into one opaque digest, so it can be
+// diffed against a prior run's snapshot - see Explain.
+func (p *Pipeline) InputDigests(taskName string) (map[string]string, error) {
+	t := p.taskByName(taskName)
+	if t == nil {
+		return nil, fmt.Errorf("InputDigests: task %q not found", taskName)
+	}
+
+	patterns := append([]string(nil), t.inputs...)
+	for _, discover := range t.inputDiscoverers {
+		discovered, err := discover()
+		if err != nil {
+			return nil, fmt.Errorf("task %q: discovering inputs: %w", taskName, err)
+		}
+		patterns = append(patterns, discovered...)
+	}
+
+	hashes, err := cache.ExpandInputs(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("task %q: %w", taskName, err)
+	}
+
+	digests := make(map[string]string, len(hashes))
+	for _, h := range hashes {
+		digests[h.Path] = h.SHA256
+	}
+	return digests, nil
+}
+
+// Explain reports why taskName would run, comparing its current
+// InputDigests against previous - a prior run's snapshot, however the
+// caller chose to persist one - mirroring what a --why <task> CLI mode
+// would print for a cache miss: the specific paths whose digest
+// differs. previous == nil is "nothing cached yet": every current input
+// is reported as changed.
+//
+// Explain is a static comparison, not a live Executor decision - it
+// doesn't know whether a cache actually holds an entry for the result,
+// only whether the inputs look like they would produce the same one.
+// Skip/NoCache annotations are reported without needing any digest at
+// all, since they preempt the comparison entirely.
+func (p *Pipeline) Explain(taskName string, previous map[string]string) (TaskEvent, error) {
+	t := p.taskByName(taskName)
+	if t == nil {
+		return TaskEvent{}, fmt.Errorf("Explain: task %q not found", taskName)
+	}
+	if t.skipReason != "" {
+		return TaskEvent{Name: taskName, Decision: "skipped", Reason: t.skipReason}, nil
+	}
+	if t.noCacheReason != "" {
+		return TaskEvent{Name: taskName, Decision: "uncached", Reason: t.noCacheReason}, nil
+	}
+
+	current, err := p.InputDigests(taskName)
+	if err != nil {
+		return TaskEvent{}, err
+	}
+	key, err := p.CacheKey(taskName)
+	if err != nil {
+		return TaskEvent{}, err
+	}
+
+	var changed []string
+	for path, digest := range current {
+		if previous[path] != digest {
+			changed = append(changed, path)
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+
+	event := TaskEvent{Name: taskName, ActionKey: key, InputDigest: key, ChangedFiles: changed}
+	switch {
+	case len(previous) == 0:
+		event.Decision = "miss"
+		event.Reason = "no prior snapshot"
+	case len(changed) == 0:
+		event.Decision = "hit"
+		event.Reason = "no input changed"
+	default:
+		event.Decision = "miss"
+		event.Reason = fmt.Sprintf("%d input(s) changed", len(changed))
+	}
+	return event, nil
+}
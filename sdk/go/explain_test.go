@@ -0,0 +1,174 @@
+package sykli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sykli.dev/go/actioncache"
+)
+
+func TestSkipAndNoCacheRoundTripThroughJSON(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...").Skip("flaky on this platform")
+	p.Task("deploy").Run("./deploy.sh").NoCache("has side effects Inputs() can't capture")
+
+	var buf bytes.Buffer
+	if err := p.EmitTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(&buf, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := emitJSON(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	build := getTaskMap(result, "build")
+	if build["skip"] != "flaky on this platform" {
+		t.Errorf("expected skip reason to round-trip, got %v", build["skip"])
+	}
+	deploy := getTaskMap(result, "deploy")
+	if deploy["no_cache"] != "has side effects Inputs() can't capture" {
+		t.Errorf("expected no_cache reason to round-trip, got %v", deploy["no_cache"])
+	}
+}
+
+func TestExecutorSkipNeverReachesTarget(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...").Skip("under investigation")
+
+	target := &countingTarget{}
+	results, err := NewExecutor().Run(context.Background(), p, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.calls["build"] != 0 {
+		t.Fatalf("expected Skip to prevent RunTask, got %d calls", target.calls["build"])
+	}
+	if r := results["build"]; !r.Success {
+		t.Errorf("expected a skipped task to report success, got %+v", r)
+	}
+}
+
+func TestExecutorNoCacheNeverHitsEvenWithMatchingEntry(t *testing.T) {
+	p := New()
+	p.Task("deploy").Run("./deploy.sh").NoCache("side effects")
+
+	key, err := p.CacheKey("deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newMemCache()
+	c.entries[key] = actioncache.Entry{Success: true, Output: "cached output"}
+
+	target := &countingTarget{}
+	_, err = NewExecutor().RegisterCache(c).Run(context.Background(), p, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.calls["deploy"] != 1 {
+		t.Fatalf("expected NoCache to force RunTask despite a matching cache entry, got %d calls", target.calls["deploy"])
+	}
+}
+
+func TestExecutorEventsReportsCacheHit(t *testing.T) {
+	p := New()
+	p.Task("a").Run("echo a")
+
+	key, err := p.CacheKey("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newMemCache()
+	c.entries[key] = actioncache.Entry{Success: true, Output: "cached"}
+
+	exec := NewExecutor().RegisterCache(c)
+	events := exec.Events()
+	if _, err := exec.Run(context.Background(), p, &countingTarget{}); err != nil {
+		t.Fatal(err)
+	}
+
+	ev, ok := <-events
+	if !ok {
+		t.Fatal("expected one TaskEvent")
+	}
+	if ev.Name != "a" || ev.Decision != "hit" {
+		t.Errorf("expected a cache-hit TaskEvent, got %+v", ev)
+	}
+	if _, ok := <-events; ok {
+		t.Error("expected Events channel to be closed after Run returns")
+	}
+}
+
+func TestExplainReportsSkipWithoutHashing(t *testing.T) {
+	p := New()
+	p.Task("build").Skip("paused")
+
+	ev, err := p.Explain("build", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Decision != "skipped" || ev.Reason != "paused" {
+		t.Errorf("expected skipped decision, got %+v", ev)
+	}
+}
+
+func TestExplainReportsChangedInputFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := New()
+	p.Task("build").Run("go build ./...").Inputs(file)
+
+	previous, err := p.InputDigests("build")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(file, []byte("package main\n\n// changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ev, err := p.Explain("build", previous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Decision != "miss" {
+		t.Errorf("expected a miss after editing the only input, got %+v", ev)
+	}
+	if len(ev.ChangedFiles) != 1 || ev.ChangedFiles[0] != file {
+		t.Errorf("expected ChangedFiles to name %q, got %v", file, ev.ChangedFiles)
+	}
+}
+
+func TestExplainReportsHitWhenNothingChanged(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := New()
+	p.Task("build").Run("go build ./...").Inputs(file)
+
+	previous, err := p.InputDigests("build")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ev, err := p.Explain("build", previous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Decision != "hit" || len(ev.ChangedFiles) != 0 {
+		t.Errorf("expected a clean hit with nothing changed, got %+v", ev)
+	}
+}
@@ -0,0 +1,178 @@
+package sykli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/google/go-jsonnet"
+)
+
+// =============================================================================
+// EXTERNAL PIPELINE GENERATION (JSONNET / CUE)
+// =============================================================================
+
+// externalPipeline is the data-language-facing schema that Jsonnet and CUE
+// files evaluate into. It mirrors the subset of jsonTask that's meaningful
+// to author directly, rather than today's internal EmitTo output - there's
+// no point round-tripping mount resources or auto-computed fields like
+// references or output_keys through hand-written config.
+type externalPipeline struct {
+	Tasks []externalTask `json:"tasks"`
+}
+
+type externalTask struct {
+	Name      string              `json:"name" yaml:"name"`
+	Command   string              `json:"command" yaml:"command"`
+	Container string              `json:"container,omitempty" yaml:"container,omitempty"`
+	Workdir   string              `json:"workdir,omitempty" yaml:"workdir,omitempty"`
+	Env       map[string]string   `json:"env,omitempty" yaml:"env,omitempty"`
+	Inputs    []string            `json:"inputs,omitempty" yaml:"inputs,omitempty"`
+	Outputs   map[string]string   `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	DependsOn []string            `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	When      string              `json:"when,omitempty" yaml:"when,omitempty"`
+	Secrets   []string            `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	Matrix    map[string][]string `json:"matrix,omitempty" yaml:"matrix,omitempty"`
+	Retry     int                 `json:"retry,omitempty" yaml:"retry,omitempty"`
+	Timeout   int                 `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// LoadJsonnet evaluates a Jsonnet file into a *Pipeline built through the
+// same Task/After/Matrix calls Go callers use, so it gets the same
+// validation, cycle detection, and EmitTo output.
+func LoadJsonnet(path string) (*Pipeline, error) {
+	out, err := evalJsonnetFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadJsonnet: %w", err)
+	}
+	return pipelineFromExternalJSON(out)
+}
+
+// LoadCUE evaluates a CUE file into a *Pipeline the same way LoadJsonnet
+// does.
+func LoadCUE(path string) (*Pipeline, error) {
+	out, err := evalCUEFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadCUE: %w", err)
+	}
+	return pipelineFromExternalJSON(out)
+}
+
+// Include merges tasks defined in an external Jsonnet (.jsonnet/.libsonnet)
+// or CUE (.cue) file into this pipeline, letting Go code compose
+// hand-written tasks with data-language-generated ones (e.g. a matrix
+// expansion that's awkward to express as a Go loop).
+//
+// A ".json" path is treated differently: it's a bundle manifest written by
+// another sykli Go module's EmitIncludeManifest, publishing reusable
+// Templates and named Directories/CacheVolumes rather than tasks (see
+// IncludeManifest). An optional namespace prefixes the bundle's template
+// and cache names so two included bundles - or a bundle and the local
+// pipeline - can't collide.
+func (p *Pipeline) Include(path string, namespace ...string) *Pipeline {
+	if strings.HasSuffix(path, ".json") {
+		return p.includeManifest(path, namespace...)
+	}
+
+	var out string
+	var err error
+	switch {
+	case strings.HasSuffix(path, ".jsonnet"), strings.HasSuffix(path, ".libsonnet"):
+		out, err = evalJsonnetFile(path)
+	case strings.HasSuffix(path, ".cue"):
+		out, err = evalCUEFile(path)
+	default:
+		log.Panic().Str("path", path).Msg("Include: unsupported file extension (expected .jsonnet, .libsonnet, .cue, or .json)")
+	}
+	if err != nil {
+		log.Panic().Str("path", path).Err(err).Msg("Include: failed to evaluate external pipeline")
+	}
+
+	var ext externalPipeline
+	if err := json.Unmarshal([]byte(out), &ext); err != nil {
+		log.Panic().Str("path", path).Err(err).Msg("Include: external pipeline did not match the expected schema")
+	}
+	applyExternalPipeline(p, &ext)
+	return p
+}
+
+func pipelineFromExternalJSON(out string) (*Pipeline, error) {
+	var ext externalPipeline
+	if err := json.Unmarshal([]byte(out), &ext); err != nil {
+		return nil, fmt.Errorf("decoding evaluated output: %w", err)
+	}
+	p := New()
+	applyExternalPipeline(p, &ext)
+	return p, nil
+}
+
+// applyExternalPipeline reconstructs tasks from the decoded external
+// schema by driving the ordinary fluent API, so every field still goes
+// through the same validation the Go builder would apply.
+func applyExternalPipeline(into *Pipeline, ext *externalPipeline) {
+	for _, et := range ext.Tasks {
+		t := into.Task(et.Name)
+		if et.Command != "" {
+			t.Run(et.Command)
+		}
+		if et.Container != "" {
+			t.Container(et.Container)
+		}
+		if et.Workdir != "" {
+			t.Workdir(et.Workdir)
+		}
+		for k, v := range et.Env {
+			t.Env(k, v)
+		}
+		if len(et.Inputs) > 0 {
+			t.Inputs(et.Inputs...)
+		}
+		for name, outPath := range et.Outputs {
+			t.Output(name, outPath)
+		}
+		if len(et.DependsOn) > 0 {
+			t.After(et.DependsOn...)
+		}
+		if et.When != "" {
+			t.When(et.When)
+		}
+		if len(et.Secrets) > 0 {
+			t.Secrets(et.Secrets...)
+		}
+		for key, values := range et.Matrix {
+			t.Matrix(key, values...)
+		}
+		if et.Retry > 0 {
+			t.Retry(et.Retry)
+		}
+		if et.Timeout > 0 {
+			t.Timeout(et.Timeout)
+		}
+	}
+}
+
+// evalJsonnetFile evaluates a Jsonnet file to its final JSON output.
+func evalJsonnetFile(path string) (string, error) {
+	vm := jsonnet.MakeVM()
+	return vm.EvaluateFile(path)
+}
+
+// evalCUEFile evaluates a CUE file to its final JSON output.
+func evalCUEFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading %q: %w", path, err)
+	}
+	ctx := cuecontext.New()
+	val := ctx.CompileBytes(data)
+	if val.Err() != nil {
+		return "", fmt.Errorf("compiling %q: %w", path, val.Err())
+	}
+	b, err := val.MarshalJSON()
+	if err != nil {
+		return "", fmt.Errorf("marshaling %q: %w", path, err)
+	}
+	return string(b), nil
+}
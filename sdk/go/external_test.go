@@ -0,0 +1,114 @@
+package sykli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testJsonnetPipeline = `
+{
+  tasks: [
+    { name: "build", command: "go build ./..." },
+    { name: "test", command: "go test ./...", depends_on: ["build"] },
+  ],
+}
+`
+
+const testCUEPipeline = `
+tasks: [
+	{ name: "build", command: "go build ./..." },
+]
+`
+
+func TestLoadJsonnetBuildsPipeline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ci.jsonnet")
+	if err := os.WriteFile(path, []byte(testJsonnetPipeline), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadJsonnet(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test := findTaskByName(result, "test")
+	deps := test["depends_on"].([]interface{})
+	if len(deps) != 1 || deps[0] != "build" {
+		t.Errorf("expected test to depend on build, got %v", deps)
+	}
+}
+
+func TestLoadCUEBuildsPipeline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ci.cue")
+	if err := os.WriteFile(path, []byte(testCUEPipeline), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadCUE(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findTaskByName(result, "build") == nil {
+		t.Error("expected build task from CUE pipeline")
+	}
+}
+
+func TestIncludeMergesExternalTasksIntoExistingPipeline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "extra.jsonnet")
+	if err := os.WriteFile(path, []byte(`{ tasks: [{ name: "lint", command: "golangci-lint run" }] }`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := New()
+	p.Task("build").Run("go build ./...")
+	p.Include(path)
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findTaskByName(result, "build") == nil || findTaskByName(result, "lint") == nil {
+		t.Errorf("expected both build and lint tasks, got %v", result["tasks"])
+	}
+}
+
+func TestIncludeUnsupportedExtensionPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for unsupported Include file extension")
+		}
+	}()
+	p := New()
+	p.Include("pipeline.yaml")
+}
+
+func TestLoadJsonnetCycleStillDetected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cycle.jsonnet")
+	src := `{
+  tasks: [
+    { name: "a", command: "echo a", depends_on: ["b"] },
+    { name: "b", command: "echo b", depends_on: ["a"] },
+  ],
+}`
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadJsonnet(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := emitJSON(p); err == nil {
+		t.Error("expected cycle error from jsonnet-generated pipeline")
+	}
+}
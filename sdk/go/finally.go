@@ -0,0 +1,111 @@
+package sykli
+
+import "fmt"
+
+// =============================================================================
+// FINALLY TASKS
+// =============================================================================
+
+// Finally registers a task that always runs after every normal task in the
+// pipeline terminates, regardless of success or failure. Finally tasks are
+// emitted under a separate top-level `finally: []` array - they never
+// participate in the main DAG's cycle detection, and a finally task's own
+// failure does not cascade to other finally tasks. Only Matrix is
+// unsupported; everything else (Inputs, Env, Container, Secret, Timeout,
+// When, After) works the same as on a normal Task.
+//
+// A finally task may only After(...) other finally tasks - use RunsOn on a
+// normal task if you need a condition keyed off the main DAG's outcome.
+func (p *Pipeline) Finally(name string) *Task {
+	if name == "" {
+		log.Panic().Msg("finally task name cannot be empty")
+	}
+	for _, existing := range p.finallyTasks {
+		if existing.name == name {
+			log.Panic().Str("task", name).Msg("finally task already exists")
+		}
+	}
+	t := &Task{
+		pipeline:  p,
+		name:      name,
+		env:       make(map[string]string),
+		mounts:    make([]Mount, 0),
+		outputs:   make(map[string]string),
+		isFinally: true,
+	}
+	log.Debug().Str("task", name).Msg("registered finally task")
+	p.finallyTasks = append(p.finallyTasks, t)
+	return t
+}
+
+// validateFinallyTasks checks that every finally task's command is set,
+// that After(...) edges stay within the finally subgraph, and that the
+// finally subgraph itself has no cycle.
+func (p *Pipeline) validateFinallyTasks() error {
+	names := make(map[string]bool, len(p.finallyTasks))
+	for _, t := range p.finallyTasks {
+		names[t.name] = true
+	}
+
+	for _, t := range p.finallyTasks {
+		if t.command == "" {
+			return fmt.Errorf("finally task %q has no command", t.name)
+		}
+		for _, dep := range t.dependsOn {
+			if !names[dep] {
+				return fmt.Errorf("finally task %q may only After() other finally tasks, but depends on %q", t.name, dep)
+			}
+		}
+	}
+
+	if cycle := detectCycleIn(p.finallyTasks); cycle != nil {
+		return fmt.Errorf("dependency cycle detected among finally tasks: %s", formatCyclePath(cycle))
+	}
+	return nil
+}
+
+// detectCycleIn runs the same three-color DFS as Pipeline.detectCycle over
+// an arbitrary task slice, so the finally subgraph can be checked for
+// cycles independently of the main DAG.
+func detectCycleIn(tasks []*Task) []string {
+	deps := make(map[string][]string, len(tasks))
+	color := make(map[string]int, len(tasks))
+	parent := make(map[string]string)
+	for _, t := range tasks {
+		deps[t.name] = t.dependsOn
+		color[t.name] = white
+	}
+
+	var dfs func(node string) []string
+	dfs = func(node string) []string {
+		color[node] = gray
+		for _, dep := range deps[node] {
+			if color[dep] == gray {
+				cycle := []string{dep}
+				current := node
+				for current != dep {
+					cycle = append([]string{current}, cycle...)
+					current = parent[current]
+				}
+				return append([]string{dep}, cycle...)
+			}
+			if color[dep] == white {
+				parent[dep] = node
+				if cycle := dfs(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		color[node] = black
+		return nil
+	}
+
+	for _, t := range tasks {
+		if color[t.name] == white {
+			if cycle := dfs(t.name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package sykli
+
+import "testing"
+
+func TestFinallyTaskEmittedSeparately(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+	p.Finally("notify").Run("./notify.sh")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, raw := range result["tasks"].([]interface{}) {
+		if raw.(map[string]interface{})["name"] == "notify" {
+			t.Fatal("finally task must not appear in tasks[]")
+		}
+	}
+
+	finally := result["finally"].([]interface{})
+	if len(finally) != 1 || finally[0].(map[string]interface{})["name"] != "notify" {
+		t.Errorf("expected finally=[notify], got %v", finally)
+	}
+}
+
+func TestFinallyTaskCanDependOnAnotherFinallyTask(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+	p.Finally("collect-logs").Run("./collect-logs.sh")
+	p.Finally("notify").Run("./notify.sh").After("collect-logs")
+
+	if _, err := emitJSON(p); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestFinallyCycleDetected(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+	p.Finally("a").Run("echo a").After("b")
+	p.Finally("b").Run("echo b").After("a")
+
+	if _, err := emitJSON(p); err == nil {
+		t.Error("expected cycle error among finally tasks")
+	}
+}
+
+func TestFinallyCannotDependOnNormalTask(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+	p.Finally("notify").Run("./notify.sh").After("build")
+
+	if _, err := emitJSON(p); err == nil {
+		t.Error("expected error: finally task depending on a normal task")
+	}
+}
+
+func TestFinallyDuplicateNamePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for duplicate finally task name")
+		}
+	}()
+	p := New()
+	p.Finally("notify").Run("./notify.sh")
+	p.Finally("notify").Run("./notify.sh")
+}
+
+func TestFinallyEmptyNamePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty finally task name")
+		}
+	}()
+	p := New()
+	p.Finally("")
+}
+
+func TestFinallyMatrixPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic calling Matrix on a finally task")
+		}
+	}()
+	p := New()
+	p.Finally("notify").Run("./notify.sh").Matrix("os", "linux")
+}
@@ -0,0 +1,134 @@
+package sykli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+)
+
+// =============================================================================
+// TEMPLATE/RESOURCE INCLUDE BUNDLES
+// =============================================================================
+
+// IncludeManifest is the data-only shape a sykli Go module publishes for
+// another pipeline's Include(path, namespace) to consume: its Templates and
+// named Directories/CacheVolumes, but no tasks. It deliberately mirrors
+// declarativeTemplate's data-only subset of Template - Extends() chains are
+// flattened by the publishing module before EmitIncludeManifest runs, so
+// the manifest carries plain settings only, never Go closures.
+type IncludeManifest struct {
+	Templates   []ManifestTemplate `json:"templates,omitempty"`
+	Directories []ManifestDir      `json:"directories,omitempty"`
+	Caches      []ManifestCache    `json:"caches,omitempty"`
+}
+
+// ManifestTemplate is one published template's flattened settings.
+type ManifestTemplate struct {
+	Name      string            `json:"name"`
+	Container string            `json:"container,omitempty"`
+	Workdir   string            `json:"workdir,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// ManifestDir is one published directory resource.
+type ManifestDir struct {
+	Path  string   `json:"path"`
+	Globs []string `json:"globs,omitempty"`
+}
+
+// ManifestCache is one published cache volume.
+type ManifestCache struct {
+	Name    string `json:"name"`
+	Sharing string `json:"sharing,omitempty"`
+}
+
+// EmitIncludeManifest writes p's templates and named directories/caches as
+// an IncludeManifest, for another pipeline to pick up with
+// Include("bundle.json", "namespace"). Call this instead of Emit() from a
+// Go module whose only purpose is publishing a shared template library.
+func (p *Pipeline) EmitIncludeManifest(w io.Writer) error {
+	names := make([]string, 0, len(p.templates))
+	for name := range p.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	m := IncludeManifest{}
+	for _, name := range names {
+		chain, err := resolveTemplateChain([]*Template{p.templates[name]})
+		if err != nil {
+			return err
+		}
+		tmpl := mergeTemplateChain(chain)
+		var env map[string]string
+		if len(tmpl.env) > 0 {
+			env = tmpl.env
+		}
+		m.Templates = append(m.Templates, ManifestTemplate{
+			Name:      name,
+			Container: tmpl.container,
+			Workdir:   tmpl.workdir,
+			Env:       env,
+		})
+	}
+	for _, d := range p.dirs {
+		m.Directories = append(m.Directories, ManifestDir{Path: d.path, Globs: d.globs})
+	}
+	for _, c := range p.caches {
+		m.Caches = append(m.Caches, ManifestCache{Name: c.name, Sharing: string(c.sharing)})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// includeManifest reads and merges a bundle manifest from path into p,
+// prefixing template and cache names with "namespace:" when one is given so
+// an included bundle can't collide with the local pipeline's own names.
+func (p *Pipeline) includeManifest(path string, namespace ...string) *Pipeline {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Panic().Str("path", path).Err(err).Msg("Include: failed to read bundle manifest")
+	}
+
+	var m IncludeManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		log.Panic().Str("path", path).Err(err).Msg("Include: bundle manifest did not match the expected schema")
+	}
+
+	var ns string
+	if len(namespace) > 0 {
+		ns = namespace[0]
+	}
+	qualify := func(name string) string {
+		if ns == "" {
+			return name
+		}
+		return ns + ":" + name
+	}
+
+	for _, d := range m.Directories {
+		p.Dir(d.Path).Glob(d.Globs...)
+	}
+	for _, c := range m.Caches {
+		cache := p.Cache(qualify(c.Name))
+		if c.Sharing != "" {
+			cache.Sharing(CacheSharing(c.Sharing))
+		}
+	}
+	for _, mt := range m.Templates {
+		tmpl := p.Template(qualify(mt.Name))
+		if mt.Container != "" {
+			tmpl.Container(mt.Container)
+		}
+		if mt.Workdir != "" {
+			tmpl.Workdir(mt.Workdir)
+		}
+		for k, v := range mt.Env {
+			tmpl.Env(k, v)
+		}
+	}
+	return p
+}
@@ -0,0 +1,106 @@
+package sykli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeManifest(t *testing.T, build func(*Pipeline)) string {
+	t.Helper()
+	pub := New()
+	build(pub)
+
+	var buf bytes.Buffer
+	if err := pub.EmitIncludeManifest(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestIncludeManifestMergesTemplatesUnderNamespace(t *testing.T) {
+	path := writeManifest(t, func(pub *Pipeline) {
+		pub.Template("golang").Container("golang:1.21").Env("CGO_ENABLED", "0")
+	})
+
+	p := New()
+	p.Include(path, "shared")
+
+	tmpl, ok := p.templates["shared:golang"]
+	if !ok {
+		t.Fatalf("expected template %q, got %v", "shared:golang", p.templates)
+	}
+	if tmpl.container != "golang:1.21" {
+		t.Errorf("expected container golang:1.21, got %q", tmpl.container)
+	}
+	if tmpl.env["CGO_ENABLED"] != "0" {
+		t.Errorf("expected CGO_ENABLED=0, got %v", tmpl.env)
+	}
+}
+
+func TestIncludeManifestMergesCachesAndDirsUnderNamespace(t *testing.T) {
+	path := writeManifest(t, func(pub *Pipeline) {
+		pub.Dir("./vendor/shared")
+		pub.Cache("go-mod").Sharing(CacheLocked)
+	})
+
+	p := New()
+	p.Include(path, "shared")
+
+	foundDir := false
+	for _, d := range p.dirs {
+		if d.path == "./vendor/shared" {
+			foundDir = true
+		}
+	}
+	if !foundDir {
+		t.Errorf("expected directory ./vendor/shared, got %v", p.dirs)
+	}
+
+	foundCache := false
+	for _, c := range p.caches {
+		if c.name == "shared:go-mod" {
+			foundCache = true
+			if c.sharing != CacheLocked {
+				t.Errorf("expected sharing mode to round-trip, got %q", c.sharing)
+			}
+		}
+	}
+	if !foundCache {
+		t.Errorf("expected cache shared:go-mod, got %v", p.caches)
+	}
+}
+
+func TestIncludeManifestWithoutNamespaceKeepsNames(t *testing.T) {
+	path := writeManifest(t, func(pub *Pipeline) {
+		pub.Template("golang").Container("golang:1.21")
+	})
+
+	p := New()
+	p.Include(path)
+
+	if _, ok := p.templates["golang"]; !ok {
+		t.Fatalf("expected template %q without a namespace, got %v", "golang", p.templates)
+	}
+}
+
+func TestEmitIncludeManifestExcludesTasks(t *testing.T) {
+	pub := New()
+	pub.Task("build").Run("go build ./...")
+	pub.Template("golang").Container("golang:1.21")
+
+	var buf bytes.Buffer
+	if err := pub.EmitIncludeManifest(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(buf.Bytes(), []byte("build")) {
+		t.Errorf("expected EmitIncludeManifest to omit tasks, got %s", buf.String())
+	}
+}
@@ -0,0 +1,144 @@
+// Package compile holds the DAG validation and leveling logic behind
+// Pipeline.Compile. It knows nothing about tasks, containers, or any other
+// DSL concept - it only sees a flat list of named nodes and the names they
+// depend on, so it can be reused by anything in sdk/go that needs to
+// validate or schedule a dependency graph without linking the rest of the
+// DSL.
+package compile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Node is one graph node: a name and the names of the nodes it depends on.
+type Node struct {
+	Name      string
+	DependsOn []string
+}
+
+// Graph is a validated dependency graph built from a flat list of Nodes.
+// New rejects duplicate names, dependencies on names that don't exist, and
+// cycles, so any *Graph returned successfully is safe to schedule.
+type Graph struct {
+	order  []string
+	byName map[string]Node
+}
+
+// New validates nodes and returns a Graph, or an error describing the
+// first problem found: a duplicate name, a dependency on an unknown node,
+// or a cycle (reported as the offending path).
+func New(nodes []Node) (*Graph, error) {
+	byName := make(map[string]Node, len(nodes))
+	order := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		if _, dup := byName[n.Name]; dup {
+			return nil, fmt.Errorf("duplicate task name %q", n.Name)
+		}
+		byName[n.Name] = n
+		order = append(order, n.Name)
+	}
+	for _, n := range nodes {
+		for _, dep := range n.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("task %q depends on unknown task %q", n.Name, dep)
+			}
+		}
+	}
+
+	g := &Graph{order: order, byName: byName}
+	if cycle := g.detectCycle(); cycle != nil {
+		return nil, fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+	}
+	return g, nil
+}
+
+// Color constants for DFS cycle detection, mirroring sykli.Pipeline's own
+// detectCycle/dfsDetectCycle/reconstructCycle (this is that same algorithm,
+// generalized to plain Nodes instead of *Task).
+const (
+	white = iota
+	gray
+	black
+)
+
+func (g *Graph) detectCycle() []string {
+	color := make(map[string]int, len(g.order))
+	parent := make(map[string]string, len(g.order))
+	for _, name := range g.order {
+		color[name] = white
+	}
+	for _, name := range g.order {
+		if color[name] == white {
+			if cycle := g.dfsDetectCycle(name, color, parent); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+func (g *Graph) dfsDetectCycle(node string, color map[string]int, parent map[string]string) []string {
+	color[node] = gray
+	for _, dep := range g.byName[node].DependsOn {
+		if color[dep] == gray {
+			return g.reconstructCycle(node, dep, parent)
+		}
+		if color[dep] == white {
+			parent[dep] = node
+			if cycle := g.dfsDetectCycle(dep, color, parent); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	color[node] = black
+	return nil
+}
+
+func (g *Graph) reconstructCycle(from, to string, parent map[string]string) []string {
+	cycle := []string{to}
+	current := from
+	for current != to {
+		cycle = append([]string{current}, cycle...)
+		current = parent[current]
+	}
+	return append([]string{to}, cycle...)
+}
+
+// Levels returns the graph's topological wavefronts: level 0 holds every
+// node with no dependencies, level 1 holds every node whose dependencies
+// are all satisfied by level 0, and so on. Nodes in the same level have no
+// edge between them, directly or transitively, so a scheduler is free to
+// run an entire level in parallel. Order within a level follows the order
+// Nodes were given in.
+func (g *Graph) Levels() [][]string {
+	depth := make(map[string]int, len(g.order))
+	var resolve func(name string) int
+	resolve = func(name string) int {
+		if d, ok := depth[name]; ok {
+			return d
+		}
+		max := -1
+		for _, dep := range g.byName[name].DependsOn {
+			if d := resolve(dep); d > max {
+				max = d
+			}
+		}
+		depth[name] = max + 1
+		return depth[name]
+	}
+
+	var maxDepth int
+	for _, name := range g.order {
+		if d := resolve(name); d > maxDepth {
+			maxDepth = d
+		}
+	}
+
+	levels := make([][]string, maxDepth+1)
+	for _, name := range g.order {
+		d := depth[name]
+		levels[d] = append(levels[d], name)
+	}
+	return levels
+}
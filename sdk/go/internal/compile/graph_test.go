@@ -0,0 +1,57 @@
+package compile
+
+import "testing"
+
+func TestNewRejectsDuplicateNames(t *testing.T) {
+	_, err := New([]Node{{Name: "build"}, {Name: "build"}})
+	if err == nil {
+		t.Fatal("expected error for duplicate name")
+	}
+}
+
+func TestNewRejectsUnknownDependency(t *testing.T) {
+	_, err := New([]Node{{Name: "build", DependsOn: []string{"missing"}}})
+	if err == nil {
+		t.Fatal("expected error for unknown dependency")
+	}
+}
+
+func TestNewRejectsCycle(t *testing.T) {
+	_, err := New([]Node{
+		{Name: "a", DependsOn: []string{"b"}},
+		{Name: "b", DependsOn: []string{"a"}},
+	})
+	if err == nil {
+		t.Fatal("expected error for cycle")
+	}
+}
+
+func TestLevelsOrdersByDependencyDepth(t *testing.T) {
+	g, err := New([]Node{
+		{Name: "test"},
+		{Name: "lint"},
+		{Name: "build", DependsOn: []string{"test", "lint"}},
+		{Name: "publish", DependsOn: []string{"build"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	levels := g.Levels()
+	if len(levels) != 3 {
+		t.Fatalf("expected 3 levels, got %d: %v", len(levels), levels)
+	}
+	if len(levels[0]) != 2 || len(levels[1]) != 1 || levels[1][0] != "build" || len(levels[2]) != 1 || levels[2][0] != "publish" {
+		t.Errorf("unexpected levels: %v", levels)
+	}
+}
+
+func TestLevelsHandlesDisconnectedNodes(t *testing.T) {
+	g, err := New([]Node{{Name: "a"}, {Name: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	levels := g.Levels()
+	if len(levels) != 1 || len(levels[0]) != 2 {
+		t.Errorf("expected both nodes in a single level, got %v", levels)
+	}
+}
@@ -0,0 +1,93 @@
+package sykli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/moby/buildkit/client/llb"
+)
+
+// =============================================================================
+// BUILDKIT LLB EXPORT
+// =============================================================================
+
+// EmitLLB lowers the v2 pipeline graph into a BuildKit LLB definition and
+// writes its serialized form to w. The result can be fed straight to
+// `buildctl build --local context=. <...> | buildctl build --local` style
+// invocations without a separate sykli runner in the loop.
+//
+// Directory resources become llb.Local states, cache mounts become
+// llb.AddMount with llb.AsPersistentCacheDir, and each container task
+// becomes an Image().Run(...) chain in dependency order. Tasks without a
+// Container() are skipped - LLB only describes containerized execution.
+func (p *Pipeline) EmitLLB(w io.Writer) error {
+	if cycle := p.detectCycle(); cycle != nil {
+		return fmt.Errorf("dependency cycle detected: %s", formatCyclePath(cycle))
+	}
+
+	states := make(map[string]llb.State, len(p.tasks))
+	var last llb.State
+	haveLast := false
+
+	for _, t := range p.tasks {
+		if t.container == "" {
+			log.Debug().Str("task", t.name).Msg("skipping non-container task in LLB export")
+			continue
+		}
+
+		st := llb.Image(t.container)
+
+		if t.workdir != "" {
+			st = st.Dir(t.workdir)
+		}
+
+		var runOpts []llb.RunOption
+		runOpts = append(runOpts, llb.Shlex(t.command))
+		for _, m := range t.mounts {
+			switch m.mountType {
+			case "directory":
+				local := llb.Local(m.resource, llb.LocalUniqueID(m.resource))
+				runOpts = append(runOpts, llb.AddMount(m.path, local))
+			case "cache":
+				runOpts = append(runOpts, llb.AddMount(m.path, llb.Scratch(), llb.AsPersistentCacheDir(m.resource, llb.CacheMountShared)))
+			}
+		}
+
+		envKeys := make([]string, 0, len(t.env))
+		for k := range t.env {
+			envKeys = append(envKeys, k)
+		}
+		sort.Strings(envKeys)
+		for _, k := range envKeys {
+			st = st.AddEnv(k, t.env[k])
+		}
+
+		root := st.Run(runOpts...).Root()
+		for outName, outPath := range t.outputs {
+			log.Debug().Str("task", t.name).Str("output", outName).Str("path", outPath).Msg("registering LLB output")
+		}
+
+		states[t.name] = root
+		last = root
+		haveLast = true
+	}
+
+	if !haveLast {
+		return fmt.Errorf("no container tasks to export as LLB")
+	}
+
+	def, err := last.Marshal(context.Background())
+	if err != nil {
+		return fmt.Errorf("marshal LLB definition: %w", err)
+	}
+
+	for _, dt := range def.Def {
+		if _, err := w.Write(dt); err != nil {
+			return fmt.Errorf("write LLB definition: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package sykli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEmitLLBNoContainerTasksFails(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test ./...")
+
+	var buf bytes.Buffer
+	if err := p.EmitLLB(&buf); err == nil {
+		t.Error("expected error when no container tasks are present")
+	}
+}
+
+func TestEmitLLBCycleFails(t *testing.T) {
+	p := New()
+	p.Task("a").Container("alpine").Run("true").After("b")
+	p.Task("b").Container("alpine").Run("true").After("a")
+
+	var buf bytes.Buffer
+	if err := p.EmitLLB(&buf); err == nil {
+		t.Error("expected error for cyclic dependency graph")
+	}
+}
+
+func TestEmitLLBBasicContainerTask(t *testing.T) {
+	p := New()
+	src := p.Dir(".")
+	cache := p.Cache("go-mod")
+
+	p.Task("build").
+		Container("golang:1.21").
+		Mount(src, "/src").
+		MountCache(cache, "/go/pkg/mod").
+		Workdir("/src").
+		Run("go build ./...")
+
+	var buf bytes.Buffer
+	if err := p.EmitLLB(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Error("expected non-empty LLB definition output")
+	}
+}
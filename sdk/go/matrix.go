@@ -0,0 +1,108 @@
+package sykli
+
+import "sort"
+
+// =============================================================================
+// MATRIX EXCLUDE / INCLUDE
+// =============================================================================
+
+// MatrixExclude drops any computed combination that matches every key/value
+// pair in entry. Every key in entry must reference a dimension already
+// declared via Matrix. Callable multiple times; each call adds one more
+// entry to prune.
+func (t *Task) MatrixExclude(entry map[string]string) *Task {
+	if t.isFinally {
+		log.Panic().Str("task", t.name).Msg("MatrixExclude is not supported on Finally tasks")
+	}
+	if len(entry) == 0 {
+		log.Panic().Str("task", t.name).Msg("MatrixExclude: entry cannot be empty")
+	}
+	for key := range entry {
+		if _, ok := t.matrix[key]; !ok {
+			log.Panic().Str("task", t.name).Str("key", key).Msg("MatrixExclude: key is not a declared matrix dimension")
+		}
+	}
+	t.matrixExclude = append(t.matrixExclude, entry)
+	return t
+}
+
+// MatrixInclude adds entry as an extra combination on top of the matrix's
+// cartesian product, even if it introduces a key that isn't a declared
+// dimension - the common CI convention for appending one-off cells (e.g. an
+// extra OS/version pairing not worth its own dimension). Callable multiple
+// times.
+func (t *Task) MatrixInclude(entry map[string]string) *Task {
+	if t.isFinally {
+		log.Panic().Str("task", t.name).Msg("MatrixInclude is not supported on Finally tasks")
+	}
+	if len(entry) == 0 {
+		log.Panic().Str("task", t.name).Msg("MatrixInclude: entry cannot be empty")
+	}
+	t.matrixInclude = append(t.matrixInclude, entry)
+	return t
+}
+
+// MatrixExpand toggles materializing the final combination list (dimensions
+// expanded to their cartesian product, excludes pruned, includes appended)
+// into the emitted `matrix.combinations` array, so a runner can execute it
+// directly instead of computing it itself.
+func (t *Task) MatrixExpand() *Task {
+	t.matrixExpand = true
+	return t
+}
+
+// expandMatrixCombinations computes the cartesian product of the task's
+// matrix dimensions, drops combinations matched by any MatrixExclude entry,
+// and appends the MatrixInclude entries.
+func expandMatrixCombinations(t *Task) []map[string]string {
+	keys := make([]string, 0, len(t.matrix))
+	for k := range t.matrix {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	combos := []map[string]string{{}}
+	for _, key := range keys {
+		var next []map[string]string
+		for _, combo := range combos {
+			for _, value := range t.matrix[key] {
+				cell := make(map[string]string, len(combo)+1)
+				for k, v := range combo {
+					cell[k] = v
+				}
+				cell[key] = value
+				next = append(next, cell)
+			}
+		}
+		combos = next
+	}
+
+	var pruned []map[string]string
+	for _, combo := range combos {
+		if matchesAnyExclude(combo, t.matrixExclude) {
+			continue
+		}
+		pruned = append(pruned, combo)
+	}
+
+	pruned = append(pruned, t.matrixInclude...)
+	return pruned
+}
+
+// matchesAnyExclude reports whether combo matches every key/value pair of
+// at least one exclude entry.
+func matchesAnyExclude(combo map[string]string, excludes []map[string]string) bool {
+	for _, exclude := range excludes {
+		matches := true
+		for k, v := range exclude {
+			if combo[k] != v {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package sykli
+
+import "testing"
+
+func TestMatrixExcludeEmitsExactCell(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test").
+		Matrix("os", "linux", "macos").
+		Matrix("go", "1.21", "1.22", "1.23").
+		MatrixExclude(map[string]string{"os": "macos", "go": "1.21"})
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrix := findTaskByName(result, "test")["matrix"].(map[string]interface{})
+	exclude := matrix["exclude"].([]interface{})
+	if len(exclude) != 1 {
+		t.Fatalf("expected 1 exclude entry, got %v", exclude)
+	}
+	entry := exclude[0].(map[string]interface{})
+	if entry["os"] != "macos" || entry["go"] != "1.21" {
+		t.Errorf("unexpected exclude entry: %+v", entry)
+	}
+}
+
+func TestMatrixIncludeAddsExtensionKey(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test").
+		Matrix("os", "linux").
+		MatrixInclude(map[string]string{"os": "windows", "experimental": "true"})
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrix := findTaskByName(result, "test")["matrix"].(map[string]interface{})
+	include := matrix["include"].([]interface{})
+	entry := include[0].(map[string]interface{})
+	if entry["os"] != "windows" || entry["experimental"] != "true" {
+		t.Errorf("unexpected include entry: %+v", entry)
+	}
+}
+
+func TestMatrixExpandEmitsPrunedCombinations(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test").
+		Matrix("os", "linux", "macos").
+		Matrix("go", "1.21", "1.22").
+		MatrixExclude(map[string]string{"os": "macos", "go": "1.21"}).
+		MatrixInclude(map[string]string{"os": "windows", "go": "1.22"}).
+		MatrixExpand()
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrix := findTaskByName(result, "test")["matrix"].(map[string]interface{})
+	combinations := matrix["combinations"].([]interface{})
+	// 2x2=4 combos, minus 1 excluded, plus 1 included = 4
+	if len(combinations) != 4 {
+		t.Fatalf("expected 4 combinations, got %d: %v", len(combinations), combinations)
+	}
+	for _, raw := range combinations {
+		combo := raw.(map[string]interface{})
+		if combo["os"] == "macos" && combo["go"] == "1.21" {
+			t.Error("excluded combination should not appear in combinations")
+		}
+	}
+}
+
+func TestMatrixExcludeUnknownKeyPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for MatrixExclude key not a declared dimension")
+		}
+	}()
+	p := New()
+	p.Task("test").Run("go test").Matrix("os", "linux").MatrixExclude(map[string]string{"arch": "arm64"})
+}
+
+func TestMatrixExcludeEmptyPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty MatrixExclude entry")
+		}
+	}()
+	p := New()
+	p.Task("test").Run("go test").Matrix("os", "linux").MatrixExclude(map[string]string{})
+}
+
+func TestMatrixIncludeEmptyPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty MatrixInclude entry")
+		}
+	}()
+	p := New()
+	p.Task("test").Run("go test").Matrix("os", "linux").MatrixInclude(map[string]string{})
+}
@@ -0,0 +1,131 @@
+// Package oci converts a compiled sykli task into an OCI runtime-spec
+// specs.Spec, so the pipeline can be executed directly against
+// containerd/runc without a shell wrapper translating mounts and env vars
+// itself. sykli.Task.OCISpec is the entry point; this package holds the
+// conversion logic and the plain data types it operates over, the same
+// split between SDK-declared config and runner-facing mechanics that
+// artifacts and secrets use.
+package oci
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// SpecOptions configures how Build resolves mounts and the container
+// root filesystem.
+type SpecOptions struct {
+	// Rootfs is the container's already-unpacked root filesystem, written
+	// into Spec.Root.Path. Build does not unpack Container's image itself -
+	// that's a containerd concern, not this SDK's.
+	Rootfs string
+	// CacheRoot is the host directory cache mounts are persisted under,
+	// one subdirectory per cache name, created on demand.
+	CacheRoot string
+	// TaskOutputs resolves a TaskInput mount's source directory, keyed as
+	// "<fromTask>/<outputName>". This SDK has no runner state tracking
+	// where a prior task actually wrote its output, so the caller (whatever
+	// ran that task) must supply it.
+	TaskOutputs map[string]string
+}
+
+// MountSpec is the runner-facing shape of one of a task's mounts.
+type MountSpec struct {
+	// Type is "directory", "cache", or "taskInput".
+	Type string
+	// HostPath is the resolved source directory (for "cache", this is the
+	// cache's name, joined onto SpecOptions.CacheRoot by Build).
+	HostPath string
+	// ContainerPath is the absolute mount point inside the container.
+	ContainerPath string
+	// ReadOnly marks the mount read-only (task-input mounts always are).
+	ReadOnly bool
+}
+
+// TaskSpec is the plain-data view of a compiled sykli task that Build
+// needs - no knowledge of Pipeline, resources, or the fluent builder.
+type TaskSpec struct {
+	Container string
+	Command   string
+	Workdir   string
+	Env       map[string]string
+	Mounts    []MountSpec
+}
+
+// Build converts t into an OCI runtime-spec Spec: a Process from
+// Command/Env/Workdir (run through "/bin/sh -c" the same way every other
+// sykli exporter treats Command), and bind Mounts from t.Mounts,
+// creating any missing cache directory under opts.CacheRoot. The
+// returned cleanup func releases anything Build allocated; callers
+// should call it once the container has exited even though today it has
+// nothing to release for plain bind mounts, in case a future mount kind
+// needs one.
+func Build(ctx context.Context, t TaskSpec, opts SpecOptions) (*specs.Spec, func(), error) {
+	if t.Container == "" {
+		return nil, nil, fmt.Errorf("oci: task has no container image set")
+	}
+	if t.Command == "" {
+		return nil, nil, fmt.Errorf("oci: task has no command set")
+	}
+	if opts.Rootfs == "" {
+		return nil, nil, fmt.Errorf("oci: SpecOptions.Rootfs is required")
+	}
+
+	envKeys := make([]string, 0, len(t.Env))
+	for k := range t.Env {
+		envKeys = append(envKeys, k)
+	}
+	sort.Strings(envKeys)
+	env := make([]string, 0, len(envKeys))
+	for _, k := range envKeys {
+		env = append(env, k+"="+t.Env[k])
+	}
+
+	cwd := t.Workdir
+	if cwd == "" {
+		cwd = "/"
+	}
+
+	mounts := make([]specs.Mount, 0, len(t.Mounts))
+	for _, m := range t.Mounts {
+		host := m.HostPath
+		if m.Type == "cache" {
+			if opts.CacheRoot == "" {
+				return nil, nil, fmt.Errorf("oci: task mounts cache %q but SpecOptions.CacheRoot is unset", m.HostPath)
+			}
+			host = filepath.Join(opts.CacheRoot, m.HostPath)
+			if err := os.MkdirAll(host, 0o755); err != nil {
+				return nil, nil, fmt.Errorf("oci: creating cache dir %q: %w", host, err)
+			}
+		}
+		mode := "rw"
+		if m.ReadOnly || m.Type == "taskInput" {
+			mode = "ro"
+		}
+		mounts = append(mounts, specs.Mount{
+			Destination: m.ContainerPath,
+			Type:        "bind",
+			Source:      host,
+			Options:     []string{"rbind", mode},
+		})
+	}
+
+	spec := &specs.Spec{
+		Version: specs.Version,
+		Process: &specs.Process{
+			Args: []string{"/bin/sh", "-c", t.Command},
+			Env:  env,
+			Cwd:  cwd,
+		},
+		Root:   &specs.Root{Path: opts.Rootfs},
+		Mounts: mounts,
+	}
+
+	cleanup := func() {}
+	return spec, cleanup, nil
+}
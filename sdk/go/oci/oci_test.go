@@ -0,0 +1,104 @@
+package oci
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildSetsProcessFromCommandEnvWorkdir(t *testing.T) {
+	spec, cleanup, err := Build(context.Background(), TaskSpec{
+		Container: "golang:1.21",
+		Command:   "go test ./...",
+		Workdir:   "/src",
+		Env:       map[string]string{"CI": "true"},
+	}, SpecOptions{Rootfs: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	wantArgs := []string{"/bin/sh", "-c", "go test ./..."}
+	if len(spec.Process.Args) != len(wantArgs) || spec.Process.Args[2] != "go test ./..." {
+		t.Errorf("expected args %v, got %v", wantArgs, spec.Process.Args)
+	}
+	if spec.Process.Cwd != "/src" {
+		t.Errorf("expected cwd /src, got %q", spec.Process.Cwd)
+	}
+	if len(spec.Process.Env) != 1 || spec.Process.Env[0] != "CI=true" {
+		t.Errorf("expected env [CI=true], got %v", spec.Process.Env)
+	}
+}
+
+func TestBuildDefaultsCwdToRoot(t *testing.T) {
+	spec, cleanup, err := Build(context.Background(), TaskSpec{
+		Container: "alpine",
+		Command:   "echo hi",
+	}, SpecOptions{Rootfs: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if spec.Process.Cwd != "/" {
+		t.Errorf("expected default cwd /, got %q", spec.Process.Cwd)
+	}
+}
+
+func TestBuildCreatesCacheDirUnderCacheRoot(t *testing.T) {
+	cacheRoot := t.TempDir()
+	spec, cleanup, err := Build(context.Background(), TaskSpec{
+		Container: "golang:1.21",
+		Command:   "go build ./...",
+		Mounts: []MountSpec{
+			{Type: "cache", HostPath: "go-mod-cache", ContainerPath: "/root/go/pkg/mod"},
+		},
+	}, SpecOptions{Rootfs: t.TempDir(), CacheRoot: cacheRoot})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	wantSrc := filepath.Join(cacheRoot, "go-mod-cache")
+	if len(spec.Mounts) != 1 || spec.Mounts[0].Source != wantSrc {
+		t.Errorf("expected cache mount source %q, got %+v", wantSrc, spec.Mounts)
+	}
+}
+
+func TestBuildCacheMountWithoutCacheRootErrors(t *testing.T) {
+	_, _, err := Build(context.Background(), TaskSpec{
+		Container: "golang:1.21",
+		Command:   "go build ./...",
+		Mounts:    []MountSpec{{Type: "cache", HostPath: "go-mod-cache", ContainerPath: "/root/go/pkg/mod"}},
+	}, SpecOptions{Rootfs: t.TempDir()})
+	if err == nil {
+		t.Error("expected error for cache mount without CacheRoot")
+	}
+}
+
+func TestBuildTaskInputMountIsReadOnly(t *testing.T) {
+	spec, cleanup, err := Build(context.Background(), TaskSpec{
+		Container: "alpine",
+		Command:   "./package.sh",
+		Mounts: []MountSpec{
+			{Type: "taskInput", HostPath: t.TempDir(), ContainerPath: "/app"},
+		},
+	}, SpecOptions{Rootfs: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if len(spec.Mounts) != 1 || spec.Mounts[0].Options[1] != "ro" {
+		t.Errorf("expected read-only task-input mount, got %+v", spec.Mounts)
+	}
+}
+
+func TestBuildRequiresContainerAndCommand(t *testing.T) {
+	if _, _, err := Build(context.Background(), TaskSpec{Command: "echo hi"}, SpecOptions{Rootfs: t.TempDir()}); err == nil {
+		t.Error("expected error for missing container image")
+	}
+	if _, _, err := Build(context.Background(), TaskSpec{Container: "alpine"}, SpecOptions{Rootfs: t.TempDir()}); err == nil {
+		t.Error("expected error for missing command")
+	}
+}
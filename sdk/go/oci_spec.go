@@ -0,0 +1,74 @@
+package sykli
+
+import (
+	"context"
+	"fmt"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"sykli.dev/go/oci"
+)
+
+// =============================================================================
+// OCI RUNTIME-SPEC EXPORT
+// =============================================================================
+
+// OCISpec converts t into an OCI runtime-spec specs.Spec, so it can be
+// handed directly to containerd/runc without a sykli runner translating
+// mounts and env vars itself. Directory and cache mounts on t become bind
+// mounts (cache mounts are persisted under opts.CacheRoot, one
+// subdirectory per cache name); each InputFrom becomes a read-only mount
+// of the producer task's output, resolved through
+// opts.TaskOutputs["<task>/<output>"] since this SDK has no runner state
+// tracking where a prior task actually wrote its output. Secret and SSH
+// mounts are skipped - OCISpec only describes what containerd/runc itself
+// understands.
+//
+// The returned cleanup func releases nothing: the cache directories
+// OCISpec creates under opts.CacheRoot are left in place so the cache
+// persists across runs. Calling it once the container has exited is safe.
+func (t *Task) OCISpec(ctx context.Context, opts oci.SpecOptions) (*specs.Spec, func(), error) {
+	mounts := make([]oci.MountSpec, 0, len(t.mounts)+len(t.taskInputs))
+	for _, m := range t.mounts {
+		switch m.mountType {
+		case "directory":
+			mounts = append(mounts, oci.MountSpec{
+				Type:          "directory",
+				HostPath:      m.sourcePath,
+				ContainerPath: m.path,
+				ReadOnly:      m.readOnly,
+			})
+		case "cache":
+			mounts = append(mounts, oci.MountSpec{
+				Type:          "cache",
+				HostPath:      m.cacheName,
+				ContainerPath: m.path,
+				ReadOnly:      m.readOnly,
+			})
+		}
+	}
+	for _, ti := range t.taskInputs {
+		key := ti.fromTask + "/" + ti.outputName
+		host, ok := opts.TaskOutputs[key]
+		if !ok {
+			return nil, nil, fmt.Errorf("OCISpec: task %q needs output %q from %q, but opts.TaskOutputs[%q] is unset", t.name, ti.outputName, ti.fromTask, key)
+		}
+		mounts = append(mounts, oci.MountSpec{
+			Type:          "taskInput",
+			HostPath:      host,
+			ContainerPath: ti.destPath,
+			ReadOnly:      true,
+		})
+	}
+
+	spec, cleanup, err := oci.Build(ctx, oci.TaskSpec{
+		Container: t.container,
+		Command:   t.command,
+		Workdir:   t.workdir,
+		Env:       t.env,
+		Mounts:    mounts,
+	}, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("OCISpec: task %q: %w", t.name, err)
+	}
+	return spec, cleanup, nil
+}
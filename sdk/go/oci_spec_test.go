@@ -0,0 +1,74 @@
+package sykli
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"sykli.dev/go/oci"
+)
+
+func TestOCISpecConvertsDirectoryAndCacheMounts(t *testing.T) {
+	p := New()
+	src := p.Dir(".")
+	cache := p.Cache("go-mod")
+	task := p.Task("build").Container("golang:1.21").Run("go build ./...").
+		Mount(src, "/src").
+		MountCache(cache, "/root/go/pkg/mod")
+
+	cacheRoot := t.TempDir()
+	spec, cleanup, err := task.OCISpec(context.Background(), oci.SpecOptions{
+		Rootfs:    t.TempDir(),
+		CacheRoot: cacheRoot,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if len(spec.Mounts) != 2 {
+		t.Fatalf("expected 2 mounts, got %+v", spec.Mounts)
+	}
+	if spec.Mounts[0].Destination != "/src" || spec.Mounts[0].Source != "." {
+		t.Errorf("expected directory mount from %q, got %+v", ".", spec.Mounts[0])
+	}
+	wantCache := filepath.Join(cacheRoot, "go-mod")
+	if spec.Mounts[1].Destination != "/root/go/pkg/mod" || spec.Mounts[1].Source != wantCache {
+		t.Errorf("expected cache mount source %q, got %+v", wantCache, spec.Mounts[1])
+	}
+}
+
+func TestOCISpecResolvesTaskInputFromOpts(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build -o /out/app").Output("binary", "/out/app")
+	pkg := p.Task("package").Container("alpine").Run("./package.sh").
+		InputFrom("build", "binary", "/app")
+
+	hostDir := t.TempDir()
+	spec, cleanup, err := pkg.OCISpec(context.Background(), oci.SpecOptions{
+		Rootfs:      t.TempDir(),
+		TaskOutputs: map[string]string{"build/binary": hostDir},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if len(spec.Mounts) != 1 || spec.Mounts[0].Source != hostDir || spec.Mounts[0].Destination != "/app" {
+		t.Errorf("expected task-input mount from %q to /app, got %+v", hostDir, spec.Mounts)
+	}
+	if spec.Mounts[0].Options[1] != "ro" {
+		t.Errorf("expected task-input mount to be read-only, got %+v", spec.Mounts[0].Options)
+	}
+}
+
+func TestOCISpecMissingTaskOutputErrors(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build -o /out/app").Output("binary", "/out/app")
+	pkg := p.Task("package").Container("alpine").Run("./package.sh").
+		InputFrom("build", "binary", "/app")
+
+	if _, _, err := pkg.OCISpec(context.Background(), oci.SpecOptions{Rootfs: t.TempDir()}); err == nil {
+		t.Error("expected error when opts.TaskOutputs is missing the producer's output")
+	}
+}
@@ -0,0 +1,180 @@
+package sykli
+
+import "sort"
+
+// =============================================================================
+// PACKAGE ARTIFACT PRODUCER
+// =============================================================================
+
+// PackageFormat selects which distro package format(s) a Package builder
+// produces.
+type PackageFormat string
+
+const (
+	FormatDeb  PackageFormat = "deb"
+	FormatRPM  PackageFormat = "rpm"
+	FormatAPK  PackageFormat = "apk"
+	FormatArch PackageFormat = "archlinux"
+)
+
+// packagerImages maps each format to the nfpm-capable image used to build it.
+var packagerImages = map[PackageFormat]string{
+	FormatDeb:  "goreleaser/nfpm:latest",
+	FormatRPM:  "goreleaser/nfpm:latest",
+	FormatAPK:  "goreleaser/nfpm:latest",
+	FormatArch: "goreleaser/nfpm:latest",
+}
+
+// PackageBuilder declaratively describes a distro package (deb/rpm/apk/arch)
+// built from an upstream task's outputs, nfpm-style. Build() desugars it
+// into one container task per format.
+type PackageBuilder struct {
+	pipeline   *Pipeline
+	name       string
+	fromTask   string
+	version    string
+	arch       string
+	maintainer string
+	depends    []string
+	files      map[string]string // local path (relative to the source task's "artifacts" output) -> dest path in the package
+	systemd    string
+	formats    []PackageFormat
+}
+
+// Package starts a distro package spec for name. Call Build() once the spec
+// is complete to materialize one container task per selected format.
+func (p *Pipeline) Package(name string) *PackageBuilder {
+	if name == "" {
+		log.Panic().Msg("package name cannot be empty")
+	}
+	return &PackageBuilder{
+		pipeline: p,
+		name:     name,
+		files:    make(map[string]string),
+	}
+}
+
+// From names the upstream task whose Output("artifacts", dir) supplies the
+// files this package installs. Omit it for packages built purely from
+// locally-checked-in files.
+func (pb *PackageBuilder) From(taskName string) *PackageBuilder {
+	pb.fromTask = taskName
+	return pb
+}
+
+// Version sets the package version (required).
+func (pb *PackageBuilder) Version(v string) *PackageBuilder {
+	pb.version = v
+	return pb
+}
+
+// Arch sets the target architecture, e.g. "amd64".
+func (pb *PackageBuilder) Arch(arch string) *PackageBuilder {
+	pb.arch = arch
+	return pb
+}
+
+// Maintainer sets the package maintainer field.
+func (pb *PackageBuilder) Maintainer(m string) *PackageBuilder {
+	pb.maintainer = m
+	return pb
+}
+
+// Depends records a runtime package dependency on the builder. Call multiple
+// times to add more. Build does not pass these to the packaging tasks.
+func (pb *PackageBuilder) Depends(dep string) *PackageBuilder {
+	pb.depends = append(pb.depends, dep)
+	return pb
+}
+
+// Files records a mapping of local file paths to destinations inside the
+// package. Destination paths must be unique across all Files calls. Build
+// does not pass the mapping to the packaging tasks.
+func (pb *PackageBuilder) Files(mapping map[string]string) *PackageBuilder {
+	for src, dest := range mapping {
+		if existing, ok := pb.destTaken(dest); ok {
+			log.Panic().Str("package", pb.name).Str("dest", dest).Str("src1", existing).Str("src2", src).Msg("Files: destination path is mapped from two different sources")
+		}
+		pb.files[src] = dest
+	}
+	return pb
+}
+
+// destTaken reports whether dest is already claimed by a different source path.
+func (pb *PackageBuilder) destTaken(dest string) (string, bool) {
+	for src, d := range pb.files {
+		if d == dest {
+			return src, true
+		}
+	}
+	return "", false
+}
+
+// Systemd records a systemd unit file on the builder. Build does not pass
+// it to the packaging tasks, so it is not installed with the package.
+func (pb *PackageBuilder) Systemd(unitFile string) *PackageBuilder {
+	pb.systemd = unitFile
+	return pb
+}
+
+// Formats selects which package format(s) to build. Call once with every
+// format you want, or multiple times to accumulate.
+func (pb *PackageBuilder) Formats(formats ...PackageFormat) *PackageBuilder {
+	pb.formats = append(pb.formats, formats...)
+	return pb
+}
+
+// Build validates the spec and materializes one container task per selected
+// format, each producing a named Output (e.g. "myapp.deb") that downstream
+// tasks can consume with InputFrom(name, "myapp.deb", destPath). Each task
+// hands nfpm only the name, version, arch and maintainer (as NFPM_* env
+// vars) plus the From task's "artifacts" output at /pkgroot; values set with
+// Depends, Files and Systemd are not used.
+func (pb *PackageBuilder) Build() []*Task {
+	if pb.version == "" {
+		log.Panic().Str("package", pb.name).Msg("Package: Version is required")
+	}
+	if len(pb.formats) == 0 {
+		log.Panic().Str("package", pb.name).Msg("Package: at least one format must be selected via Formats(...)")
+	}
+
+	formats := make([]PackageFormat, 0, len(pb.formats))
+	seen := make(map[PackageFormat]bool)
+	for _, f := range pb.formats {
+		if !seen[f] {
+			seen[f] = true
+			formats = append(formats, f)
+		}
+	}
+	sort.Slice(formats, func(i, j int) bool { return formats[i] < formats[j] })
+
+	tasks := make([]*Task, 0, len(formats))
+	for _, format := range formats {
+		artifactName := pb.name + "." + string(formatExt(format))
+		outPath := "/out/" + artifactName
+
+		t := pb.pipeline.Task(pb.name + ":package:" + string(format)).
+			Container(packagerImages[format]).
+			Env("NFPM_NAME", pb.name).
+			Env("NFPM_VERSION", pb.version).
+			Env("NFPM_ARCH", pb.arch).
+			Env("NFPM_MAINTAINER", pb.maintainer).
+			Run("nfpm package --packager " + string(format) + " --target " + outPath).
+			Output(artifactName, outPath)
+
+		if pb.fromTask != "" {
+			t.InputFrom(pb.fromTask, "artifacts", "/pkgroot")
+		}
+
+		tasks = append(tasks, t)
+	}
+	return tasks
+}
+
+// formatExt returns the file extension nfpm produces for a given format.
+func formatExt(f PackageFormat) PackageFormat {
+	if f == FormatArch {
+		return "pkg.tar.zst"
+	}
+	return f
+}
@@ -0,0 +1,71 @@
+package sykli
+
+import "testing"
+
+func TestPackageBuildProducesOneTaskPerFormat(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build -o ./app").Output("artifacts", "./dist")
+
+	tasks := p.Package("myapp").
+		From("build").
+		Version("1.2.3").
+		Arch("amd64").
+		Maintainer("CI <ci@example.com>").
+		Depends("libc6").
+		Files(map[string]string{"./app": "/usr/bin/app"}).
+		Formats(FormatDeb, FormatRPM).
+		Build()
+
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(tasks))
+	}
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonTasks := result["tasks"].([]interface{})
+	if len(jsonTasks) != 3 { // build + 2 package tasks
+		t.Fatalf("expected 3 tasks in JSON, got %d", len(jsonTasks))
+	}
+}
+
+func TestPackageFilesCollisionPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for colliding Files destination")
+		}
+	}()
+	p := New()
+	p.Package("myapp").
+		Files(map[string]string{"./app": "/usr/bin/app"}).
+		Files(map[string]string{"./other": "/usr/bin/app"})
+}
+
+func TestPackageEmptyVersionPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty version")
+		}
+	}()
+	p := New()
+	p.Package("myapp").Formats(FormatDeb).Build()
+}
+
+func TestPackageNoFormatsPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic when no formats are selected")
+		}
+	}()
+	p := New()
+	p.Package("myapp").Version("1.0.0").Build()
+}
+
+func TestPackageDeduplicatesFormats(t *testing.T) {
+	p := New()
+	tasks := p.Package("myapp").Version("1.0.0").Formats(FormatDeb, FormatDeb).Build()
+	if len(tasks) != 1 {
+		t.Errorf("expected duplicate formats to collapse to 1 task, got %d", len(tasks))
+	}
+}
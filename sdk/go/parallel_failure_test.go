@@ -0,0 +1,84 @@
+package sykli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestContinueOnErrorEmitted(t *testing.T) {
+	p := New()
+	g := p.Parallel("checks",
+		p.Task("lint").Run("go vet ./..."),
+		p.Task("test").Run("go test ./..."),
+	)
+	g.ContinueOnError().MaxFailures(1)
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups := result["groups"].([]interface{})
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	group := groups[0].(map[string]interface{})
+	if group["name"] != "checks" {
+		t.Errorf("expected group name=checks, got %v", group)
+	}
+	policy := group["failure_policy"].(map[string]interface{})
+	if policy["on_error"] != "continue" || policy["max_failures"] != float64(1) {
+		t.Errorf("unexpected failure_policy: %v", policy)
+	}
+	if getTaskMap(result, "lint")["group"] != "checks" {
+		t.Error("expected lint task to carry group=checks")
+	}
+}
+
+func TestFailurePolicyOmittedWhenUnset(t *testing.T) {
+	p := New()
+	p.Parallel("checks", p.Task("lint").Run("go vet ./..."))
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	group := result["groups"].([]interface{})[0].(map[string]interface{})
+	if _, ok := group["failure_policy"]; ok {
+		t.Error("expected no failure_policy when ContinueOnError/MaxFailures were never called")
+	}
+}
+
+func TestMaxFailuresNonPositivePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for non-positive MaxFailures")
+		}
+	}()
+	p := New()
+	g := p.Parallel("checks", p.Task("lint").Run("go vet ./..."))
+	g.MaxFailures(0)
+}
+
+func TestContinueOnErrorRoundTripsThroughParse(t *testing.T) {
+	p := New()
+	p.Parallel("checks",
+		p.Task("lint").Run("go vet ./..."),
+		p.Task("test").Run("go test ./..."),
+	).ContinueOnError().MaxFailures(2)
+
+	var buf bytes.Buffer
+	if err := p.EmitTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(&buf, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.groups) != 1 {
+		t.Fatalf("expected 1 parsed group, got %d", len(parsed.groups))
+	}
+	g := parsed.groups[0]
+	if g.name != "checks" || g.onError != "continue" || g.maxFailures != 2 || len(g.tasks) != 2 {
+		t.Errorf("unexpected parsed group: %+v", g)
+	}
+}
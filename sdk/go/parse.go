@@ -0,0 +1,409 @@
+package sykli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// =============================================================================
+// PARSE (EMIT INVERSE)
+// =============================================================================
+
+// Parse reads a pipeline previously written by EmitTo back into a *Pipeline,
+// in the given format. Derived fields (References, OutputKeys, Matrix
+// combinations, WhenAST) are not reconstructed directly - rebuilding the
+// source-of-truth fields below and calling EmitTo again recomputes them
+// identically, the same way a freshly-authored pipeline would. A secret
+// resource's source type is reconstructed with a best-effort heuristic
+// (see secretSourceFromRef) since EmitTo collapses it to a single string.
+func Parse(r io.Reader, format EmitFormat) (*Pipeline, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read pipeline: %w", err)
+	}
+
+	var doc jsonPipeline
+	switch format {
+	case FormatYAML:
+		var generic interface{}
+		if err := yaml.Unmarshal(raw, &generic); err != nil {
+			return nil, fmt.Errorf("unmarshal YAML: %w", err)
+		}
+		asJSON, err := json.Marshal(generic)
+		if err != nil {
+			return nil, fmt.Errorf("convert YAML to JSON: %w", err)
+		}
+		if err := json.Unmarshal(asJSON, &doc); err != nil {
+			return nil, fmt.Errorf("unmarshal pipeline: %w", err)
+		}
+	default:
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return nil, fmt.Errorf("unmarshal pipeline: %w", err)
+		}
+	}
+
+	p := New()
+	resources := parseResources(p, doc.Resources)
+
+	groupTasks := make(map[string][]*Task)
+	for _, jt := range doc.Tasks {
+		t := p.Task(jt.Name)
+		applyJSONTask(t, jt, resources)
+		if jt.Group != "" {
+			groupTasks[jt.Group] = append(groupTasks[jt.Group], t)
+		}
+	}
+	for _, jt := range doc.Finally {
+		applyJSONTask(p.Finally(jt.Name), jt, resources)
+	}
+
+	for _, jg := range doc.Groups {
+		g := p.Parallel(jg.Name, groupTasks[jg.Name]...)
+		if jg.FailurePolicy != nil {
+			if jg.FailurePolicy.OnError == "continue" {
+				g.ContinueOnError()
+			}
+			if jg.FailurePolicy.MaxFailures > 0 {
+				g.MaxFailures(jg.FailurePolicy.MaxFailures)
+			}
+		}
+	}
+
+	if doc.AutoCancel != nil {
+		p.AutoCancel(AutoCancelPolicy{
+			OnPush:        doc.AutoCancel.OnPush,
+			OnPullRequest: doc.AutoCancel.OnPullRequest,
+			Scope:         CancelScope(doc.AutoCancel.Scope),
+			Pending:       doc.AutoCancel.Pending,
+			Running:       doc.AutoCancel.Running,
+			PullRequests:  doc.AutoCancel.PullRequests,
+		})
+	}
+
+	if doc.Concurrency != nil {
+		var opts []ConcurrencyOption
+		if doc.Concurrency.CancelInProgress {
+			opts = append(opts, CancelInProgress())
+		}
+		if doc.Concurrency.QueueDepth > 0 {
+			opts = append(opts, QueueDepth(doc.Concurrency.QueueDepth))
+		}
+		if doc.Concurrency.WaitForPrevious {
+			opts = append(opts, WaitForPrevious())
+		}
+		p.Concurrency(doc.Concurrency.Group, opts...)
+	}
+
+	if doc.ArtifactStore != nil {
+		p.ArtifactStore(parseArtifactStore(doc.ArtifactStore))
+	}
+
+	for host, ra := range doc.DockerRegistriesAuth {
+		auth := p.RegistryAuth(host)
+		if ra.Username != "" {
+			auth.Username(ra.Username)
+		}
+		if ra.UsernameSecret != "" {
+			auth.UsernameFromSecret(ra.UsernameSecret)
+		}
+		if ra.Password != "" {
+			auth.Password(ra.Password)
+		}
+		if ra.PasswordSecret != "" {
+			auth.PasswordFromSecret(ra.PasswordSecret)
+		}
+		if ra.AuthFile != "" {
+			auth.AuthFile(ra.AuthFile)
+		}
+	}
+
+	if doc.Version != "" {
+		p.EmitVersion(doc.Version)
+	}
+
+	return p, nil
+}
+
+// parseResources rebuilds the pipeline's directory/cache/ssh/secret
+// resources from the wire "resources" map and returns them keyed by ID so
+// applyJSONTask's mounts can look them back up.
+func parseResources(p *Pipeline, res map[string]jsonResource) map[string]interface{} {
+	byID := make(map[string]interface{}, len(res))
+	for id, r := range res {
+		switch r.Type {
+		case "directory":
+			byID[id] = p.Dir(r.Path)
+		case "cache":
+			byID[id] = p.Cache(r.Name)
+		case "ssh":
+			s := p.SSH(r.Name)
+			if r.Path != "" {
+				s.FromKey(r.Path)
+			} else {
+				s.FromAgent()
+			}
+			byID[id] = s
+		case "secret":
+			s := p.Secret(r.Name)
+			switch source, ref := secretSourceFromRef(r.Path); source {
+			case "env":
+				s.FromEnv(ref)
+			case "vault":
+				s.FromVault(ref)
+			case "file":
+				s.FromFile(ref)
+			}
+			byID[id] = s
+		}
+	}
+	return byID
+}
+
+// secretSourceFromRef reverses secretSourceRef's collapsing of a secret's
+// source into one string: an "env:" prefix means FromEnv, a "vault://"
+// prefix means FromVault, and anything else is treated as a FromFile path -
+// the same ambiguity FromFile and FromVault already share in the wire
+// format, best-effort resolved by the most common case.
+func secretSourceFromRef(ref string) (source, value string) {
+	switch {
+	case ref == "":
+		return "", ""
+	case strings.HasPrefix(ref, "env:"):
+		return "env", strings.TrimPrefix(ref, "env:")
+	case strings.HasPrefix(ref, "vault://"):
+		return "vault", ref
+	default:
+		return "file", ref
+	}
+}
+
+func parseArtifactStore(a *jsonArtifactStore) *ArtifactStore {
+	switch a.Type {
+	case "s3":
+		if a.Region != "" {
+			return S3(a.Bucket, WithRegion(a.Region))
+		}
+		return S3(a.Bucket)
+	case "gcs":
+		return GCS(a.Bucket)
+	default:
+		return LocalFS(a.Path)
+	}
+}
+
+// applyJSONTask rebuilds a single task's source-of-truth settings from its
+// wire representation. mounts are resolved by resource ID against the
+// resources already registered by parseResources.
+func applyJSONTask(t *Task, jt jsonTask, resources map[string]interface{}) {
+	if jt.Command != "" {
+		t.Run(jt.Command)
+	}
+	if jt.Container != "" {
+		t.Container(jt.Container)
+	}
+	if jt.Workdir != "" {
+		t.Workdir(jt.Workdir)
+	}
+	for k, v := range jt.Env {
+		t.Env(k, v)
+	}
+	if len(jt.Inputs) > 0 {
+		t.Inputs(jt.Inputs...)
+	}
+	for name, path := range jt.Outputs {
+		t.Output(name, path)
+	}
+	if len(jt.DependsOn) > 0 {
+		t.After(jt.DependsOn...)
+	}
+	if jt.When != "" {
+		t.When(jt.When)
+	}
+	if len(jt.Secrets) > 0 {
+		t.Secrets(jt.Secrets...)
+	}
+	for k, v := range jt.Matrix {
+		parseMatrixDimension(t, k, v)
+	}
+	for _, svc := range jt.Services {
+		t.Service(svc.Image, svc.Name)
+	}
+	if jt.Retry > 0 {
+		t.Retry(jt.Retry)
+	}
+	if jt.Timeout > 0 {
+		t.Timeout(jt.Timeout)
+	}
+	if jt.NonCancelable {
+		t.NonCancelable()
+	}
+	if len(jt.RunsOn) > 0 {
+		t.RunsOn(jt.RunsOn...)
+	}
+	if jt.Target != "" {
+		t.Target(jt.Target)
+	}
+	if jt.Watch != nil {
+		t.Src(jt.Watch.Src...)
+		if jt.Watch.DebounceMs > 0 {
+			t.Debounce(jt.Watch.DebounceMs)
+		}
+	}
+	if len(jt.Requires) > 0 {
+		caps := make([]Capability, len(jt.Requires))
+		for i, c := range jt.Requires {
+			caps[i] = Capability{Kind: c.Kind, Value: c.Value, ProbeCmd: c.ProbeCmd}
+		}
+		t.Requires(caps...)
+	}
+	if jt.Skip != "" {
+		t.Skip(jt.Skip)
+	}
+	if jt.NoCache != "" {
+		t.NoCache(jt.NoCache)
+	}
+	for _, m := range jt.Mounts {
+		applyJSONMount(t, m, resources)
+	}
+}
+
+// parseMatrixDimension applies one entry of a jsonTask's Matrix map back
+// onto t, routing the "exclude"/"include" pseudo-keys EmitTo folds in
+// alongside the real dimensions to MatrixExclude/MatrixInclude instead of
+// Matrix itself. "combinations" is a derived field and is skipped.
+func parseMatrixDimension(t *Task, key string, value interface{}) {
+	switch key {
+	case "combinations":
+		return
+	case "exclude":
+		for _, entry := range toStringMapSlice(value) {
+			t.MatrixExclude(entry)
+		}
+	case "include":
+		for _, entry := range toStringMapSlice(value) {
+			t.MatrixInclude(entry)
+		}
+	default:
+		values := toStringSlice(value)
+		if len(values) > 0 {
+			t.Matrix(key, values...)
+		}
+	}
+}
+
+// toStringSlice coerces a decoded JSON []interface{} of strings into
+// []string.
+func toStringSlice(v interface{}) []string {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// toStringMapSlice coerces a decoded JSON []interface{} of string maps into
+// []map[string]string.
+func toStringMapSlice(v interface{}) []map[string]string {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		entry := make(map[string]string, len(m))
+		for k, v := range m {
+			if s, ok := v.(string); ok {
+				entry[k] = s
+			}
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// applyJSONMount reconstructs a single mount by looking up its resource ID
+// among the resources parseResources already registered.
+func applyJSONMount(t *Task, m jsonMount, resources map[string]interface{}) {
+	resource, ok := resources[m.Resource]
+	if !ok {
+		return
+	}
+	switch r := resource.(type) {
+	case *Directory:
+		t.Mount(r, m.Path)
+	case *CacheVolume:
+		if m.Sharing != "" {
+			r.Sharing(CacheSharing(m.Sharing))
+		}
+		opts := mountOwnerOpts(m)
+		t.MountCache(r, m.Path, opts...)
+	case *SSHResource:
+		t.MountSSH(r, m.Path)
+	case *SecretResource:
+		opts := secretMountOpts(m)
+		t.MountSecret(r, m.Path, opts...)
+	}
+}
+
+// mountOwnerOpts translates a jsonMount's ownership/mode/readonly fields
+// into the MountOption list MountCache expects.
+func mountOwnerOpts(m jsonMount) []MountOption {
+	var opts []MountOption
+	if m.UID != nil && m.GID != nil {
+		opts = append(opts, WithOwner(*m.UID, *m.GID))
+	}
+	if m.ReadOnly {
+		opts = append(opts, WithReadOnly())
+	}
+	if mode, ok := parseOctalMode(m.Mode); ok {
+		opts = append(opts, WithMode(mode))
+	}
+	return opts
+}
+
+// parseOctalMode parses a mode string like "0400" as written by
+// WithMode/SecretMode's fmt.Sprintf("%04o", ...).
+func parseOctalMode(mode string) (int, bool) {
+	if mode == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(mode, 8, 0)
+	if err != nil {
+		return 0, false
+	}
+	return int(n), true
+}
+
+// secretMountOpts translates a jsonMount's ownership/mode/required fields
+// into the SecretMountOption list MountSecret expects.
+func secretMountOpts(m jsonMount) []SecretMountOption {
+	var opts []SecretMountOption
+	if m.UID != nil {
+		opts = append(opts, SecretUID(*m.UID))
+	}
+	if m.GID != nil {
+		opts = append(opts, SecretGID(*m.GID))
+	}
+	if m.Required {
+		opts = append(opts, SecretRequired())
+	}
+	if mode, ok := parseOctalMode(m.Mode); ok {
+		opts = append(opts, SecretMode(mode))
+	}
+	return opts
+}
@@ -0,0 +1,118 @@
+package sykli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseRoundTripsBasicPipeline(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...").Container("golang:1.21").Env("CGO_ENABLED", "0").Output("bin", "/out/app")
+	p.Task("test").Run("go test ./...").After("build")
+
+	var buf bytes.Buffer
+	if err := p.EmitTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := Parse(&buf, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := parsed.EmitTo(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := emitJSON(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := getTaskMap(result, "test")
+	deps := task["depends_on"].([]interface{})
+	if len(deps) != 1 || deps[0] != "build" {
+		t.Errorf("expected test to depend on build after round-trip, got %v", deps)
+	}
+	build := getTaskMap(result, "build")
+	if build["container"] != "golang:1.21" {
+		t.Errorf("expected container to round-trip, got %v", build["container"])
+	}
+}
+
+func TestParseRoundTripsResourcesAndMounts(t *testing.T) {
+	p := New()
+	dir := p.Dir("./src")
+	cache := p.Cache("go-mod").Sharing(CacheLocked)
+	p.Task("build").Run("go build ./...").Mount(dir, "/src").MountCache(cache, "/root/.cache/go-build")
+
+	var buf bytes.Buffer
+	if err := p.EmitTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := Parse(&buf, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := emitJSON(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := getTaskMap(result, "build")
+	mounts, ok := task["mounts"].([]interface{})
+	if !ok || len(mounts) != 2 {
+		t.Fatalf("expected 2 mounts to round-trip, got %v", task["mounts"])
+	}
+}
+
+func TestParseYAMLRoundTrip(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+	p.EmitFormat(FormatYAML)
+
+	var buf bytes.Buffer
+	if err := p.EmitTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := Parse(&buf, FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := emitJSON(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if getTaskMap(result, "build") == nil {
+		t.Errorf("expected build task to round-trip through YAML, got %v", result)
+	}
+}
+
+func TestParseReconstructsSecretSource(t *testing.T) {
+	p := New()
+	secret := p.Secret("db_pass").FromEnv("DB_PASS")
+	p.Task("deploy").Run("deploy.sh").MountSecret(secret, "/run/secrets/db_pass")
+
+	var buf bytes.Buffer
+	if err := p.EmitTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := Parse(&buf, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := emitJSON(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources := result["resources"].(map[string]interface{})
+	resource := resources["secret:db_pass"].(map[string]interface{})
+	if resource["path"] != "env:DB_PASS" {
+		t.Errorf("expected secret source to round-trip as env:DB_PASS, got %v", resource["path"])
+	}
+}
@@ -0,0 +1,36 @@
+package sykli
+
+import "sykli.dev/go/targets/podman"
+
+// =============================================================================
+// PODMAN QUADLET EXPORT
+// =============================================================================
+
+// PodmanGraph translates p's task graph into the plain-data shape
+// podman.EmitQuadlet needs to place each task, service, and cache mount as
+// a systemd Quadlet unit: DependsOn becomes After=/Requires=, a Service
+// becomes its own .container unit the task's unit depends on, and a
+// cache mount becomes a .volume unit.
+func (p *Pipeline) PodmanGraph() []podman.TaskNode {
+	nodes := make([]podman.TaskNode, len(p.tasks))
+	for i, t := range p.tasks {
+		nodes[i] = podman.TaskNode{
+			Name:      t.name,
+			Command:   t.command,
+			Image:     t.container,
+			Workdir:   t.workdir,
+			Env:       t.env,
+			DependsOn: t.dependsOn,
+		}
+		for _, svc := range t.services {
+			nodes[i].Services = append(nodes[i].Services, podman.ServiceRef{Name: svc.name, Image: svc.image})
+		}
+		for _, m := range t.mounts {
+			if m.mountType != "cache" {
+				continue
+			}
+			nodes[i].Caches = append(nodes[i].Caches, podman.CacheRef{Name: m.cacheName, Path: m.path})
+		}
+	}
+	return nodes
+}
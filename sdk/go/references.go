@@ -0,0 +1,100 @@
+package sykli
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// =============================================================================
+// TASK OUTPUT REFERENCES
+// =============================================================================
+
+// refPattern matches the `${{ tasks.<name>.outputs.<key> }}` substitution
+// token produced by Task.Ref.
+var refPattern = regexp.MustCompile(`\$\{\{\s*tasks\.([A-Za-z0-9_-]+)\.outputs\.([A-Za-z0-9_-]+)\s*\}\}`)
+
+// TaskReference records a single `${{ tasks.<name>.outputs.<key> }}`
+// substitution found in a task's command, env, or input patterns.
+type TaskReference struct {
+	task   string
+	output string
+}
+
+// Ref returns a substitution token that resolves to the named output of
+// another task at run time, e.g. `${{ tasks.build.outputs.binary }}`.
+// Embed the token in this task's Run, Env, or Inputs values - EmitTo scans
+// every task for occurrences, validates the referenced task and output
+// exist, and auto-adds a dependency edge on the referenced task if one
+// isn't already there (the same auto-dependency behavior as InputFrom).
+func (t *Task) Ref(taskName, outputKey string) string {
+	if taskName == "" {
+		log.Panic().Str("task", t.name).Msg("Ref: taskName cannot be empty")
+	}
+	if outputKey == "" {
+		log.Panic().Str("task", t.name).Msg("Ref: outputKey cannot be empty")
+	}
+	return fmt.Sprintf("${{ tasks.%s.outputs.%s }}", taskName, outputKey)
+}
+
+// resolveReferences scans every task's command, env values, and input
+// patterns for `${{ tasks.*.outputs.* }}` tokens, validates each against
+// the pipeline's other tasks, records it on the referencing task, and
+// auto-adds a dependency edge on the referenced task when one is missing.
+func (p *Pipeline) resolveReferences() error {
+	byName := make(map[string]*Task, len(p.tasks))
+	for _, t := range p.tasks {
+		byName[t.name] = t
+	}
+
+	for _, t := range p.tasks {
+		t.references = nil
+		seen := make(map[TaskReference]bool)
+
+		values := []string{t.command}
+		envKeys := make([]string, 0, len(t.env))
+		for k := range t.env {
+			envKeys = append(envKeys, k)
+		}
+		sort.Strings(envKeys)
+		for _, k := range envKeys {
+			values = append(values, t.env[k])
+		}
+		values = append(values, t.inputs...)
+
+		for _, v := range values {
+			for _, m := range refPattern.FindAllStringSubmatch(v, -1) {
+				refTaskName, outputKey := m[1], m[2]
+
+				target, ok := byName[refTaskName]
+				if !ok {
+					return fmt.Errorf("task %q references unknown task %q", t.name, refTaskName)
+				}
+				if target == t {
+					return fmt.Errorf("task %q cannot Ref its own output", t.name)
+				}
+				if _, ok := target.outputs[outputKey]; !ok {
+					return fmt.Errorf("task %q references unknown output %q on task %q", t.name, outputKey, refTaskName)
+				}
+
+				ref := TaskReference{task: refTaskName, output: outputKey}
+				if !seen[ref] {
+					seen[ref] = true
+					t.references = append(t.references, ref)
+				}
+
+				hasDep := false
+				for _, dep := range t.dependsOn {
+					if dep == refTaskName {
+						hasDep = true
+						break
+					}
+				}
+				if !hasDep {
+					t.dependsOn = append(t.dependsOn, refTaskName)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package sykli
+
+import "testing"
+
+func TestRefHappyPath(t *testing.T) {
+	p := New()
+	build := p.Task("build").Run("go build -o /out/app").Output("binary", "/out/app")
+	pkg := p.Task("package")
+	pkg.Run("cp " + pkg.Ref("build", "binary") + " .")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = build
+
+	task := findTaskByName(result, "package")
+	refs := task["references"].([]interface{})
+	if len(refs) != 1 {
+		t.Fatalf("expected 1 reference, got %v", refs)
+	}
+	ref := refs[0].(map[string]interface{})
+	if ref["task"] != "build" || ref["output"] != "binary" {
+		t.Errorf("unexpected reference: %+v", ref)
+	}
+}
+
+func TestRefAutoAddsMissingDependencyEdge(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build -o /out/app").Output("binary", "/out/app")
+	pkg := p.Task("package")
+	pkg.Run("cp " + pkg.Ref("build", "binary") + " .")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps := findTaskByName(result, "package")["depends_on"].([]interface{})
+	if len(deps) != 1 || deps[0] != "build" {
+		t.Errorf("expected depends_on=[build] to be auto-added, got %v", deps)
+	}
+}
+
+func TestRefUnknownTask(t *testing.T) {
+	p := New()
+	pkg := p.Task("package")
+	pkg.Run("cp " + pkg.Ref("ghost", "binary") + " .")
+
+	if _, err := emitJSON(p); err == nil {
+		t.Error("expected error for Ref to unknown task")
+	}
+}
+
+func TestRefUnknownOutput(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build -o /out/app").Output("binary", "/out/app")
+	pkg := p.Task("package")
+	pkg.Run("cp " + pkg.Ref("build", "nope") + " .")
+
+	if _, err := emitJSON(p); err == nil {
+		t.Error("expected error for Ref to unknown output")
+	}
+}
+
+func TestRefAlreadyDependentDoesNotDuplicateEdge(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build -o /out/app").Output("binary", "/out/app")
+	pkg := p.Task("package").After("build")
+	pkg.Run("cp " + pkg.Ref("build", "binary") + " .")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps := findTaskByName(result, "package")["depends_on"].([]interface{})
+	if len(deps) != 1 {
+		t.Errorf("expected depends_on to stay at one entry, got %v", deps)
+	}
+}
+
+func TestRefCycleViaAutoAddedEdgeDetected(t *testing.T) {
+	p := New()
+	a := p.Task("a").Output("out", "/out/a")
+	b := p.Task("b").Output("out", "/out/b")
+	a.Run("echo " + a.Ref("b", "out"))
+	b.Run("echo " + b.Ref("a", "out"))
+
+	if _, err := emitJSON(p); err == nil {
+		t.Error("expected cycle error from auto-added reference edges")
+	}
+}
+
+func TestRefEmptyArgsPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty Ref arguments")
+		}
+	}()
+	p := New()
+	t2 := p.Task("t").Run("echo hi")
+	t2.Ref("", "out")
+}
@@ -0,0 +1,208 @@
+package sykli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// =============================================================================
+// REMOTE TEMPLATE REGISTRY
+// =============================================================================
+
+// Registry holds named template constructors loaded from a git-hosted
+// bundle. Each entry builds a *Template bound to the Pipeline passed to
+// Template, so registry templates compose with the same From(...) /
+// MountCache(...) fluent API as locally-defined ones.
+type Registry struct {
+	source  string
+	entries map[string]func(p *Pipeline) *Template
+}
+
+// RegisterFunc is implemented by a bundle's Register(r *Registry) entry
+// point to publish its templates.
+type RegisterFunc func(r *Registry)
+
+// Add publishes a named template constructor. Bundles call this from their
+// Register(r *Registry) function.
+func (r *Registry) Add(name string, fn func(p *Pipeline) *Template) {
+	if name == "" {
+		log.Panic().Msg("registry template name cannot be empty")
+	}
+	if r.entries == nil {
+		r.entries = make(map[string]func(p *Pipeline) *Template)
+	}
+	r.entries[name] = fn
+}
+
+// Template builds the named template against p, panicking if the registry
+// does not publish a template with that name (mirroring the other
+// builder-style validators in this package).
+func (r *Registry) Template(p *Pipeline, name string) *Template {
+	fn, ok := r.entries[name]
+	if !ok {
+		log.Panic().Str("registry", r.source).Str("template", name).Msg("registry does not publish this template")
+	}
+	return fn(p)
+}
+
+// ImportOptions controls how Import fetches and caches a registry bundle.
+type ImportOptions struct {
+	// CacheDir overrides the default ~/.cache/sykli/registry cache root.
+	CacheDir string
+	// Offline fails closed instead of fetching over the network when the
+	// ref isn't already cached.
+	Offline bool
+}
+
+// Import fetches a versioned bundle of task templates over Git - e.g.
+// Import("git.example.com/org/sykli-presets@v1.2.0") or, pinned,
+// Import("git.example.com/org/sykli-presets@sha256:abcd...") - and returns
+// a Registry exposing every template the bundle's Register func added.
+//
+// Bundles are shallow-cloned once into <cache>/<host>/<repo>@<ref> and
+// reused on subsequent calls. On Linux the bundle is loaded as a Go plugin
+// (registry.so built ahead of time by the bundle's own CI); elsewhere sykli
+// falls back to running the bundle's subprocess-emitted JSON description,
+// which supports declarative templates only.
+func Import(ref string, opts ...ImportOptions) (*Registry, error) {
+	var opt ImportOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	host, repo, version, err := parseImportRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheRoot := opt.CacheDir
+	if cacheRoot == "" {
+		cacheRoot = defaultRegistryCacheDir()
+	}
+	bundleDir := filepath.Join(cacheRoot, host, repo+"@"+version)
+
+	if _, statErr := os.Stat(bundleDir); statErr != nil {
+		if opt.Offline {
+			return nil, fmt.Errorf("sykli: offline mode - %s is not cached at %s", ref, bundleDir)
+		}
+		if err := shallowCloneRegistry(host, repo, version, bundleDir); err != nil {
+			return nil, fmt.Errorf("sykli: fetching registry %s: %w", ref, err)
+		}
+	}
+
+	if strings.HasPrefix(version, "sha256:") {
+		if err := verifyRegistryPin(bundleDir, strings.TrimPrefix(version, "sha256:")); err != nil {
+			return nil, err
+		}
+	}
+
+	reg := &Registry{source: ref}
+	if err := loadRegistryBundle(bundleDir, reg); err != nil {
+		return nil, fmt.Errorf("sykli: loading registry %s: %w", ref, err)
+	}
+	return reg, nil
+}
+
+// parseImportRef splits "host/org/repo@ref" into its parts.
+func parseImportRef(ref string) (host, repo, version string, err error) {
+	at := strings.LastIndex(ref, "@")
+	if at < 0 {
+		return "", "", "", fmt.Errorf("sykli: import ref %q must be pinned with @<version|sha256:...>", ref)
+	}
+	path, version := ref[:at], ref[at+1:]
+	if version == "" {
+		return "", "", "", fmt.Errorf("sykli: import ref %q has an empty version", ref)
+	}
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("sykli: import ref %q must look like host/org/repo@version", ref)
+	}
+	return parts[0], parts[1], version, nil
+}
+
+// defaultRegistryCacheDir returns ~/.cache/sykli/registry, honoring XDG_CACHE_HOME.
+func defaultRegistryCacheDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "sykli", "registry")
+	}
+	return filepath.Join(os.TempDir(), "sykli", "registry")
+}
+
+// shallowCloneRegistry fetches a single-commit checkout of host/repo at ref
+// into dir using the git binary on PATH.
+func shallowCloneRegistry(host, repo, ref, dir string) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return err
+	}
+	cloneURL := (&url.URL{Scheme: "https", Host: host, Path: "/" + repo + ".git"}).String()
+
+	// A sha256: ref pins the bundle's tree content, not a git ref, and is
+	// checked afterward by verifyRegistryPin - so there's no branch name to
+	// pass to git here. Just clone the default branch and let the content
+	// hash comparison catch a mismatch.
+	args := []string{"clone", "--depth", "1"}
+	if !strings.HasPrefix(ref, "sha256:") {
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, cloneURL, dir)
+
+	cmd := exec.Command("git", args...)
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git clone %s@%s: %w", cloneURL, ref, err)
+	}
+	return nil
+}
+
+// verifyRegistryPin recomputes a content hash over the cached bundle and
+// fails if it does not match the pinned sha256, catching silent mutation of
+// a shared cache directory.
+func verifyRegistryPin(dir, wantHex string) error {
+	got, err := hashRegistryTree(dir)
+	if err != nil {
+		return fmt.Errorf("sykli: hashing registry bundle at %s: %w", dir, err)
+	}
+	if got != wantHex {
+		return fmt.Errorf("sykli: registry bundle at %s does not match pinned sha256:%s (got sha256:%s)", dir, wantHex, got)
+	}
+	return nil
+}
+
+// hashRegistryTree hashes every regular file under dir, sorted by relative
+// path, into a single deterministic digest.
+func hashRegistryTree(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		io.WriteString(h, rel)
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,160 @@
+package sykli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// =============================================================================
+// DOCKER REGISTRY AUTH
+// =============================================================================
+
+// DockerRegistryAuth holds the credentials a runner needs to pull from (or
+// push to) a private container registry, keyed by hostname so tasks never
+// have to repeat them.
+type DockerRegistryAuth struct {
+	pipeline *Pipeline
+	host     string
+
+	username       string
+	usernameSecret string // set via UsernameFromSecret instead of Username
+	password       string
+	passwordSecret string // set via PasswordFromSecret instead of Password
+	authFilePath   string // set via AuthFile, e.g. "~/.docker/config.json"
+}
+
+// RegistryAuth registers credentials for the given registry hostname (e.g.
+// "ghcr.io"). Any task whose Container image hostname matches is
+// automatically associated with it at emit time - no need to wire it per
+// task.
+func (p *Pipeline) RegistryAuth(host string) *DockerRegistryAuth {
+	if host == "" {
+		log.Panic().Msg("RegistryAuth: host cannot be empty")
+	}
+	for _, existing := range p.registryAuths {
+		if existing.host == host {
+			log.Panic().Str("host", host).Msg("RegistryAuth: host already registered")
+		}
+	}
+	r := &DockerRegistryAuth{pipeline: p, host: host}
+	log.Debug().Str("host", host).Msg("registered docker registry auth")
+	p.registryAuths = append(p.registryAuths, r)
+	return r
+}
+
+// Username sets the literal username (or a runner-resolved placeholder like
+// "$GH_USER") used to authenticate with the registry.
+func (r *DockerRegistryAuth) Username(value string) *DockerRegistryAuth {
+	if value == "" {
+		log.Panic().Str("registry", r.host).Msg("Username cannot be empty")
+	}
+	r.username = value
+	return r
+}
+
+// UsernameFromSecret resolves the username from a secret declared elsewhere
+// via Task.Secret, instead of embedding it in the pipeline file.
+func (r *DockerRegistryAuth) UsernameFromSecret(name string) *DockerRegistryAuth {
+	if name == "" {
+		log.Panic().Str("registry", r.host).Msg("UsernameFromSecret: name cannot be empty")
+	}
+	r.usernameSecret = name
+	return r
+}
+
+// Password sets the literal password (or a runner-resolved placeholder like
+// "$GH_TOKEN") used to authenticate with the registry.
+func (r *DockerRegistryAuth) Password(value string) *DockerRegistryAuth {
+	if value == "" {
+		log.Panic().Str("registry", r.host).Msg("Password cannot be empty")
+	}
+	r.password = value
+	return r
+}
+
+// PasswordFromSecret resolves the password from a secret declared elsewhere
+// via Task.Secret, instead of embedding it in the pipeline file.
+func (r *DockerRegistryAuth) PasswordFromSecret(name string) *DockerRegistryAuth {
+	if name == "" {
+		log.Panic().Str("registry", r.host).Msg("PasswordFromSecret: name cannot be empty")
+	}
+	r.passwordSecret = name
+	return r
+}
+
+// AuthFile points at a pre-existing Docker config.json containing auth for
+// this registry, as an alternative to Username/Password.
+func (r *DockerRegistryAuth) AuthFile(path string) *DockerRegistryAuth {
+	if path == "" {
+		log.Panic().Str("registry", r.host).Msg("AuthFile: path cannot be empty")
+	}
+	r.authFilePath = path
+	return r
+}
+
+// hasCredentials reports whether enough of Username/Password/AuthFile has
+// been configured for a runner to actually authenticate.
+func (r *DockerRegistryAuth) hasCredentials() bool {
+	if r.authFilePath != "" {
+		return true
+	}
+	hasUser := r.username != "" || r.usernameSecret != ""
+	hasPass := r.password != "" || r.passwordSecret != ""
+	return hasUser && hasPass
+}
+
+// registryHostOf extracts the registry hostname from a container image
+// reference, or "" if the image implicitly refers to Docker Hub (no
+// hostname segment, e.g. "golang:1.21" or "org/app:tag").
+func registryHostOf(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	first := parts[0]
+	if strings.ContainsAny(first, ".:") || first == "localhost" {
+		return first
+	}
+	return ""
+}
+
+// matchingRegistryAuth finds the auth entry for host, matching the registry
+// hostname exactly or as a "host:port" variant of a bare declared host.
+func matchingRegistryAuth(host string, auths []*DockerRegistryAuth) *DockerRegistryAuth {
+	for _, a := range auths {
+		if a.host == host || strings.HasPrefix(host, a.host+":") {
+			return a
+		}
+	}
+	return nil
+}
+
+// validateRegistryAuth checks that every declared registry auth has usable
+// credentials, and that every task referencing a private registry (a
+// container image with an explicit registry hostname) has matching auth
+// when the pipeline declares any RegistryAuth at all.
+func (p *Pipeline) validateRegistryAuth() error {
+	for _, r := range p.registryAuths {
+		if !r.hasCredentials() {
+			return fmt.Errorf("RegistryAuth %q has no usable credentials (set Username/UsernameFromSecret and Password/PasswordFromSecret, or AuthFile)", r.host)
+		}
+	}
+
+	if len(p.registryAuths) == 0 {
+		return nil
+	}
+
+	for _, t := range p.tasks {
+		if t.container == "" {
+			continue
+		}
+		host := registryHostOf(t.container)
+		if host == "" {
+			continue
+		}
+		if matchingRegistryAuth(host, p.registryAuths) == nil {
+			return fmt.Errorf("task %q references registry %q with no matching RegistryAuth declared", t.name, host)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package sykli
+
+import "testing"
+
+func TestRegistryAuthEmittedByHost(t *testing.T) {
+	p := New()
+	p.RegistryAuth("ghcr.io").Username("$GH_USER").Password("$GH_TOKEN")
+	p.Task("build").Container("ghcr.io/org/img:tag").Run("make build")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auths := result["docker_registries_auth"].(map[string]interface{})
+	entry := auths["ghcr.io"].(map[string]interface{})
+	if entry["username"] != "$GH_USER" || entry["password"] != "$GH_TOKEN" {
+		t.Errorf("unexpected registry auth entry: %+v", entry)
+	}
+}
+
+func TestRegistryAuthFromSecret(t *testing.T) {
+	p := New()
+	p.RegistryAuth("ghcr.io").UsernameFromSecret("GH_USER").PasswordFromSecret("GH_TOKEN")
+	p.Task("build").Container("ghcr.io/org/img:tag").Run("make build").Secrets("GH_USER", "GH_TOKEN")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := result["docker_registries_auth"].(map[string]interface{})["ghcr.io"].(map[string]interface{})
+	if entry["username_secret"] != "GH_USER" || entry["password_secret"] != "GH_TOKEN" {
+		t.Errorf("unexpected registry auth entry: %+v", entry)
+	}
+}
+
+func TestRegistryAuthAuthFile(t *testing.T) {
+	p := New()
+	p.RegistryAuth("registry.internal").AuthFile("~/.docker/config.json")
+	p.Task("build").Container("registry.internal/img:tag").Run("make build")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := result["docker_registries_auth"].(map[string]interface{})["registry.internal"].(map[string]interface{})
+	if entry["auth_file"] != "~/.docker/config.json" {
+		t.Errorf("unexpected registry auth entry: %+v", entry)
+	}
+}
+
+func TestRegistryAuthMissingForPrivateRegistryErrors(t *testing.T) {
+	p := New()
+	p.RegistryAuth("ghcr.io").Username("u").Password("p")
+	p.Task("build").Container("quay.io/org/img:tag").Run("make build")
+
+	if _, err := emitJSON(p); err == nil {
+		t.Error("expected error for task referencing registry with no matching auth")
+	}
+}
+
+func TestRegistryAuthDockerHubImageDoesNotRequireAuth(t *testing.T) {
+	p := New()
+	p.RegistryAuth("ghcr.io").Username("u").Password("p")
+	p.Task("build").Container("golang:1.21").Run("make build")
+
+	if _, err := emitJSON(p); err != nil {
+		t.Fatalf("expected no error for Docker Hub image, got %v", err)
+	}
+}
+
+func TestRegistryAuthIncompleteCredentialsErrors(t *testing.T) {
+	p := New()
+	p.RegistryAuth("ghcr.io").Username("u")
+	p.Task("build").Run("make build")
+
+	if _, err := emitJSON(p); err == nil {
+		t.Error("expected error for RegistryAuth with incomplete credentials")
+	}
+}
+
+func TestRegistryAuthDuplicateHostPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for duplicate RegistryAuth host")
+		}
+	}()
+	p := New()
+	p.RegistryAuth("ghcr.io")
+	p.RegistryAuth("ghcr.io")
+}
+
+func TestRegistryAuthNoneDeclaredSkipsValidation(t *testing.T) {
+	p := New()
+	p.Task("build").Container("quay.io/org/img:tag").Run("make build")
+
+	if _, err := emitJSON(p); err != nil {
+		t.Fatalf("expected no error when no RegistryAuth declared at all, got %v", err)
+	}
+}
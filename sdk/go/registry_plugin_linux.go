@@ -0,0 +1,36 @@
+//go:build linux
+
+package sykli
+
+import (
+	"fmt"
+	"path/filepath"
+	"plugin"
+)
+
+// loadRegistryBundle loads a bundle's pre-built registry.so via Go's plugin
+// package and invokes its Register(r *Registry) entry point. Bundles are
+// expected to build registry.so ahead of time (plugin mode requires the
+// loader and the bundle to share a Go toolchain and module graph), which
+// keeps this path fast and avoids shelling out to `go build` per import.
+func loadRegistryBundle(dir string, r *Registry) error {
+	soPath := filepath.Join(dir, "registry.so")
+
+	p, err := plugin.Open(soPath)
+	if err != nil {
+		return fmt.Errorf("opening plugin %s: %w", soPath, err)
+	}
+
+	sym, err := p.Lookup("Register")
+	if err != nil {
+		return fmt.Errorf("plugin %s does not export Register: %w", soPath, err)
+	}
+
+	register, ok := sym.(func(r *Registry))
+	if !ok {
+		return fmt.Errorf("plugin %s: Register has the wrong signature, want func(*sykli.Registry)", soPath)
+	}
+
+	register(r)
+	return nil
+}
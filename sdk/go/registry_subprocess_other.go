@@ -0,0 +1,57 @@
+//go:build !linux
+
+package sykli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// declarativeTemplate is the JSON shape a bundle's `package main` prints to
+// stdout when invoked with --sykli-registry-json, on platforms where Go
+// plugins aren't available. It only supports the data-only subset of
+// Template - Extends() and other Go-only composition stays Linux-only.
+type declarativeTemplate struct {
+	Name      string            `json:"name"`
+	Container string            `json:"container"`
+	Workdir   string            `json:"workdir"`
+	Env       map[string]string `json:"env"`
+}
+
+// loadRegistryBundle runs the bundle as a subprocess (`go run <dir>
+// --sykli-registry-json`) and builds Registry entries from the JSON
+// template descriptors it prints, since Go plugins require cgo and a
+// matching toolchain that isn't guaranteed off Linux.
+func loadRegistryBundle(dir string, r *Registry) error {
+	cmd := exec.Command("go", "run", dir, "--sykli-registry-json")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running bundle %s: %w (stderr: %s)", dir, err, stderr.String())
+	}
+
+	var tmpls []declarativeTemplate
+	if err := json.Unmarshal(stdout.Bytes(), &tmpls); err != nil {
+		return fmt.Errorf("parsing registry JSON from %s: %w", dir, err)
+	}
+
+	for _, dt := range tmpls {
+		dt := dt
+		r.Add(dt.Name, func(p *Pipeline) *Template {
+			t := p.Template(dt.Name)
+			if dt.Container != "" {
+				t.Container(dt.Container)
+			}
+			if dt.Workdir != "" {
+				t.Workdir(dt.Workdir)
+			}
+			for k, v := range dt.Env {
+				t.Env(k, v)
+			}
+			return t
+		})
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package sykli
+
+import "testing"
+
+func TestParseImportRef(t *testing.T) {
+	host, repo, version, err := parseImportRef("git.example.com/org/sykli-presets@v1.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host != "git.example.com" || repo != "org/sykli-presets" || version != "v1.2.0" {
+		t.Errorf("unexpected parse: host=%q repo=%q version=%q", host, repo, version)
+	}
+}
+
+func TestParseImportRefRequiresVersion(t *testing.T) {
+	if _, _, _, err := parseImportRef("git.example.com/org/sykli-presets"); err == nil {
+		t.Error("expected error for unpinned ref")
+	}
+}
+
+func TestParseImportRefRequiresOrgAndRepo(t *testing.T) {
+	if _, _, _, err := parseImportRef("git.example.com@v1.0.0"); err == nil {
+		t.Error("expected error for ref missing org/repo path")
+	}
+}
+
+func TestRegistryTemplateUnknownNamePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for unknown registry template")
+		}
+	}()
+	reg := &Registry{source: "test"}
+	p := New()
+	reg.Template(p, "does-not-exist")
+}
+
+func TestRegistryAddAndTemplate(t *testing.T) {
+	reg := &Registry{source: "test"}
+	reg.Add("rust-ci", func(p *Pipeline) *Template {
+		return p.Template("rust-ci").Container("rust:1.75")
+	})
+
+	p := New()
+	tmpl := reg.Template(p, "rust-ci")
+	if tmpl.container != "rust:1.75" {
+		t.Errorf("expected container 'rust:1.75', got %q", tmpl.container)
+	}
+}
+
+func TestImportRejectsUnpinnedRef(t *testing.T) {
+	if _, err := Import("git.example.com/org/sykli-presets"); err == nil {
+		t.Error("expected error for unpinned import ref")
+	}
+}
+
+func TestImportOfflineMissesCacheFails(t *testing.T) {
+	_, err := Import("git.example.com/org/sykli-presets@v1.2.0", ImportOptions{
+		CacheDir: t.TempDir(),
+		Offline:  true,
+	})
+	if err == nil {
+		t.Error("expected offline import of an uncached ref to fail")
+	}
+}
@@ -0,0 +1,56 @@
+package sykli
+
+// =============================================================================
+// RUN-STATUS CONDITIONS
+// =============================================================================
+
+// runStatusTokens are the status conditions a task's RunsOn may reference.
+var runStatusTokens = map[string]bool{
+	"success": true,
+	"failure": true,
+	"always":  true,
+	"changed": true,
+}
+
+// RunsOn restricts when this task runs based on the outcome of its
+// dependencies, emitted as `runs_on: [...]` alongside `depends_on`. A task
+// with `runs_on: [failure]` runs only when at least one upstream dependency
+// failed; `always` runs regardless of upstream outcome. Valid tokens are
+// "success", "failure", "always", and "changed".
+func (t *Task) RunsOn(statuses ...string) *Task {
+	if len(statuses) == 0 {
+		log.Panic().Str("task", t.name).Msg("RunsOn: at least one status must be given")
+	}
+	for _, s := range statuses {
+		if !runStatusTokens[s] {
+			log.Panic().Str("task", t.name).Str("status", s).Msg("RunsOn: unknown status token, want success/failure/always/changed")
+		}
+	}
+	t.runsOn = append(t.runsOn, statuses...)
+	return t
+}
+
+// OnFailure is sugar for RunsOn("failure").
+func (t *Task) OnFailure() *Task {
+	return t.RunsOn("failure")
+}
+
+// OnSuccess is sugar for RunsOn("success"). This is the implicit default
+// when RunsOn is never called.
+func (t *Task) OnSuccess() *Task {
+	return t.RunsOn("success")
+}
+
+// Always is sugar for RunsOn("always") - the task runs regardless of
+// whether its dependencies succeeded or failed. Combined with no
+// After(...) dependencies, this is how pipeline-final notification or
+// cleanup tasks are expressed.
+func (t *Task) Always() *Task {
+	return t.RunsOn("always")
+}
+
+// OnChanged is sugar for RunsOn("changed") - the task runs only when an
+// upstream dependency's outcome differs from its previous run.
+func (t *Task) OnChanged() *Task {
+	return t.RunsOn("changed")
+}
@@ -0,0 +1,91 @@
+package sykli
+
+import "testing"
+
+func TestRunsOnSingleStatus(t *testing.T) {
+	p := New()
+	p.Task("notify").Run("echo failed").OnFailure()
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := findTaskByName(result, "notify")
+	runsOn := task["runs_on"].([]interface{})
+	if len(runsOn) != 1 || runsOn[0] != "failure" {
+		t.Errorf("expected runs_on=[failure], got %v", runsOn)
+	}
+}
+
+func TestRunsOnMultipleStatuses(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test").RunsOn("success", "failure")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runsOn := findTaskByName(result, "test")["runs_on"].([]interface{})
+	if len(runsOn) != 2 {
+		t.Errorf("expected 2 entries, got %d", len(runsOn))
+	}
+}
+
+func TestAlwaysSugar(t *testing.T) {
+	p := New()
+	p.Task("cleanup").Run("rm -rf tmp").Always()
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runsOn := findTaskByName(result, "cleanup")["runs_on"].([]interface{})
+	if runsOn[0] != "always" {
+		t.Errorf("expected runs_on=[always], got %v", runsOn)
+	}
+}
+
+func TestRunsOnEmptyPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty RunsOn call")
+		}
+	}()
+	p := New()
+	p.Task("test").Run("go test").RunsOn()
+}
+
+func TestRunsOnUnknownTokenPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for unknown status token")
+		}
+	}()
+	p := New()
+	p.Task("test").Run("go test").RunsOn("bogus")
+}
+
+func TestRunsOnOmittedWhenUnset(t *testing.T) {
+	p := New()
+	p.Task("test").Run("go test")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := findTaskByName(result, "test")["runs_on"]; ok {
+		t.Error("expected no runs_on field when never called")
+	}
+}
+
+// findTaskByName is a small helper shared by tests that look up a task's
+// JSON object by name within an emitted pipeline.
+func findTaskByName(result map[string]interface{}, name string) map[string]interface{} {
+	for _, raw := range result["tasks"].([]interface{}) {
+		task := raw.(map[string]interface{})
+		if task["name"] == name {
+			return task
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package sykli
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// =============================================================================
+// JSON SCHEMA
+// =============================================================================
+
+// Schema returns a JSON Schema (draft-07) document describing the pipeline
+// format emitted by EmitTo, generated by reflecting over jsonPipeline and
+// its nested types so the schema can never drift from what EmitTo actually
+// writes. version is accepted for forward compatibility but currently has
+// no effect on the generated document - versions "1" and "2" differ only in
+// which optional fields are populated, not in the wire shape itself.
+func Schema(version string) []byte {
+	doc := map[string]interface{}{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"title":   "sykli pipeline",
+		"type":    "object",
+	}
+	for k, v := range reflectSchema(reflect.TypeOf(jsonPipeline{})) {
+		doc[k] = v
+	}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		log.Panic().Err(err).Msg("Schema: failed to marshal generated schema")
+	}
+	return out
+}
+
+// reflectSchema builds the "properties"/"required" pair of a JSON Schema
+// object from a struct type's `json:"..."` tags.
+func reflectSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, omitempty := parseJSONTag(tag, field.Name)
+		properties[name] = schemaForType(field.Type)
+		if !omitempty {
+			required = append(required, name)
+		}
+	}
+
+	result := map[string]interface{}{"properties": properties}
+	if len(required) > 0 {
+		result["required"] = required
+	}
+	return result
+}
+
+// parseJSONTag splits a `json:"name,omitempty"` tag into its field name
+// (falling back to fallback when the tag has none) and whether it's
+// optional.
+func parseJSONTag(tag, fallback string) (name string, omitempty bool) {
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = fallback
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+// schemaForType maps a Go field type to its JSON Schema type descriptor,
+// recursing into structs, slices, and maps.
+func schemaForType(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaForType(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": schemaForType(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": schemaForType(t.Elem()),
+		}
+	case reflect.Struct:
+		schema := map[string]interface{}{"type": "object"}
+		for k, v := range reflectSchema(t) {
+			schema[k] = v
+		}
+		return schema
+	default:
+		return map[string]interface{}{}
+	}
+}
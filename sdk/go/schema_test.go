@@ -0,0 +1,40 @@
+package sykli
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSchemaIsValidJSON(t *testing.T) {
+	raw := Schema("2")
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("Schema output is not valid JSON: %v", err)
+	}
+	if doc["type"] != "object" {
+		t.Errorf("expected top-level type object, got %v", doc["type"])
+	}
+	props, ok := doc["properties"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected properties map, got %v", doc["properties"])
+	}
+	for _, field := range []string{"version", "tasks", "resources", "finally"} {
+		if _, ok := props[field]; !ok {
+			t.Errorf("expected schema to describe field %q, got %v", field, props)
+		}
+	}
+}
+
+func TestSchemaRequiredFieldsExcludeOmitempty(t *testing.T) {
+	raw := Schema("2")
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatal(err)
+	}
+	required, _ := doc["required"].([]interface{})
+	for _, r := range required {
+		if r == "resources" {
+			t.Errorf("expected omitempty field %q to not be required", r)
+		}
+	}
+}
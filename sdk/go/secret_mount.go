@@ -0,0 +1,134 @@
+package sykli
+
+import "fmt"
+
+// =============================================================================
+// EPHEMERAL SECRET MOUNTS
+// =============================================================================
+
+// SecretResource represents a secret value that can be mounted into a task
+// as a tmpfs-backed file, rather than injected into the environment where it
+// would leak into /proc/<pid>/environ and child processes.
+type SecretResource struct {
+	pipeline *Pipeline
+	name     string
+
+	source   string // "env", "file", or "vault" - empty until one of the From* methods is called
+	envVar   string
+	filePath string
+	vaultRef string
+}
+
+// Secret registers a named secret resource. Configure where its value comes
+// from with FromEnv, FromFile, or FromVault before mounting it with
+// Task.MountSecret.
+func (p *Pipeline) Secret(name string) *SecretResource {
+	if name == "" {
+		log.Panic().Msg("secret resource name cannot be empty")
+	}
+	s := &SecretResource{pipeline: p, name: name}
+	log.Debug().Str("name", name).Msg("registered secret resource")
+	p.secretResources = append(p.secretResources, s)
+	return s
+}
+
+// FromEnv resolves the secret's value from a host environment variable at
+// task-launch time.
+func (s *SecretResource) FromEnv(envVar string) *SecretResource {
+	if envVar == "" {
+		log.Panic().Str("secret", s.name).Msg("env var name cannot be empty")
+	}
+	s.source, s.envVar = "env", envVar
+	return s
+}
+
+// FromFile resolves the secret's value by reading a file from the host at
+// task-launch time.
+func (s *SecretResource) FromFile(path string) *SecretResource {
+	if path == "" {
+		log.Panic().Str("secret", s.name).Msg("file path cannot be empty")
+	}
+	s.source, s.filePath = "file", path
+	return s
+}
+
+// FromVault resolves the secret's value from HashiCorp Vault, e.g.
+// "vault://secret/data/ci#DB_PASSWORD".
+func (s *SecretResource) FromVault(ref string) *SecretResource {
+	if ref == "" {
+		log.Panic().Str("secret", s.name).Msg("vault ref cannot be empty")
+	}
+	s.source, s.vaultRef = "vault", ref
+	return s
+}
+
+// ID returns a unique identifier for this secret resource.
+func (s *SecretResource) ID() string {
+	return "secret:" + s.name
+}
+
+// SecretMountOption customizes a secret mount's ownership, permissions, and
+// whether the task may run without the secret resolved.
+type SecretMountOption func(*Mount)
+
+// SecretUID sets the uid that should own the mounted secret file.
+func SecretUID(uid int) SecretMountOption {
+	return func(m *Mount) { m.uid, m.hasOwner = uid, true }
+}
+
+// SecretGID sets the gid that should own the mounted secret file.
+func SecretGID(gid int) SecretMountOption {
+	return func(m *Mount) { m.gid, m.hasOwner = gid, true }
+}
+
+// SecretMode overrides the default 0400 permission mode of the mounted secret file.
+func SecretMode(mode int) SecretMountOption {
+	return func(m *Mount) { m.mode = fmt.Sprintf("%04o", mode) }
+}
+
+// SecretRequired panics at plan time unless the secret resource has a
+// resolvable source (FromEnv/FromFile/FromVault) configured.
+func SecretRequired() SecretMountOption {
+	return func(m *Mount) { m.secretRequired = true }
+}
+
+// secretSourceRef returns the non-sensitive reference describing where a
+// secret's value comes from (an env var name, file path, or vault ref) -
+// never the resolved value itself.
+func secretSourceRef(s *SecretResource) string {
+	switch s.source {
+	case "env":
+		return "env:" + s.envVar
+	case "file":
+		return s.filePath
+	case "vault":
+		return s.vaultRef
+	default:
+		return ""
+	}
+}
+
+// MountSecret mounts a secret as a tmpfs-backed file at path, present only
+// for the duration of the task. Defaults to mode 0400.
+func (t *Task) MountSecret(secret *SecretResource, path string, opts ...SecretMountOption) *Task {
+	if secret == nil {
+		log.Panic().Str("task", t.name).Msg("secret resource cannot be nil")
+	}
+	if path == "" || path[0] != '/' {
+		log.Panic().Str("task", t.name).Str("path", path).Msg("mount path must be absolute (start with /)")
+	}
+	m := Mount{
+		resource:  secret.ID(),
+		path:      path,
+		mountType: "secret",
+		mode:      "0400",
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	if m.secretRequired && secret.source == "" {
+		log.Panic().Str("task", t.name).Str("secret", secret.name).Msg("MountSecret: required secret has no source configured (FromEnv/FromFile/FromVault)")
+	}
+	t.mounts = append(t.mounts, m)
+	return t
+}
@@ -0,0 +1,79 @@
+package sykli
+
+import "testing"
+
+func TestSecretResourceID(t *testing.T) {
+	p := New()
+	s := p.Secret("db_pass").FromEnv("DB_PASS")
+
+	if s.ID() != "secret:db_pass" {
+		t.Errorf("expected ID 'secret:db_pass', got %q", s.ID())
+	}
+}
+
+func TestMountSecretDefaultMode(t *testing.T) {
+	p := New()
+	s := p.Secret("db_pass").FromEnv("DB_PASS")
+	p.Task("deploy").Container("alpine").MountSecret(s, "/run/secrets/db_pass").Run("psql")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mount := result["tasks"].([]interface{})[0].(map[string]interface{})["mounts"].([]interface{})[0].(map[string]interface{})
+	if mount["type"] != "secret" || mount["resource"] != "secret:db_pass" {
+		t.Errorf("unexpected secret mount: %+v", mount)
+	}
+	if mount["mode"] != "0400" {
+		t.Errorf("expected default mode '0400', got %v", mount["mode"])
+	}
+}
+
+func TestMountSecretCustomModeAndOwner(t *testing.T) {
+	p := New()
+	s := p.Secret("db_pass").FromFile("/etc/sykli/db_pass")
+	p.Task("deploy").
+		Container("alpine").
+		MountSecret(s, "/run/secrets/db_pass", SecretUID(1000), SecretGID(1000), SecretMode(0440)).
+		Run("psql")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mount := result["tasks"].([]interface{})[0].(map[string]interface{})["mounts"].([]interface{})[0].(map[string]interface{})
+	if mount["mode"] != "0440" || mount["uid"] != float64(1000) || mount["gid"] != float64(1000) {
+		t.Errorf("unexpected mount ownership: %+v", mount)
+	}
+}
+
+func TestMountSecretRequiredWithoutSourcePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for required secret with no source")
+		}
+	}()
+	p := New()
+	s := p.Secret("db_pass")
+	p.Task("deploy").MountSecret(s, "/run/secrets/db_pass", SecretRequired())
+}
+
+func TestMountSecretRequiredWithSourceOK(t *testing.T) {
+	p := New()
+	s := p.Secret("db_pass").FromVault("vault://secret/data/ci#DB_PASSWORD")
+	p.Task("deploy").Container("alpine").MountSecret(s, "/run/secrets/db_pass", SecretRequired()).Run("psql")
+
+	if _, err := emitJSON(p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNilSecretMountPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for nil secret resource")
+		}
+	}()
+	p := New()
+	p.Task("test").MountSecret(nil, "/run/secrets/x")
+}
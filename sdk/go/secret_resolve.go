@@ -0,0 +1,35 @@
+package sykli
+
+import (
+	"context"
+	"fmt"
+
+	"sykli.dev/go/secrets"
+)
+
+// =============================================================================
+// SECRET RESOLUTION
+// =============================================================================
+
+// ResolveSecrets resolves every secret resource this pipeline declares
+// through provider, returning a map of secret name to value ready to inject
+// into a task's environment at launch time. It's the runner-side
+// counterpart to Secret/MountSecret: the SDK only emits *where* a secret
+// comes from (see secretSourceRef); ResolveSecrets is what actually fetches
+// the value, typically using secrets.New wired up to a secrets.VaultProvider
+// for any "vault://" refs.
+func (p *Pipeline) ResolveSecrets(ctx context.Context, provider secrets.Provider) (map[string]string, error) {
+	out := make(map[string]string, len(p.secretResources))
+	for _, s := range p.secretResources {
+		ref := secretSourceRef(s)
+		if ref == "" {
+			return nil, fmt.Errorf("secret %q has no source configured (FromEnv/FromFile/FromVault)", s.name)
+		}
+		value, _, err := provider.Resolve(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("resolving secret %q: %w", s.name, err)
+		}
+		out[s.name] = value
+	}
+	return out, nil
+}
@@ -0,0 +1,40 @@
+package sykli
+
+import (
+	"context"
+	"testing"
+
+	"sykli.dev/go/secrets"
+)
+
+type fakeSecretProvider map[string]string
+
+func (f fakeSecretProvider) Resolve(ctx context.Context, ref string) (string, map[string]string, error) {
+	return f[ref], nil, nil
+}
+
+func TestResolveSecretsInjectsEnvValues(t *testing.T) {
+	p := New()
+	p.Secret("db_pass").FromEnv("DB_PASS")
+	p.Secret("api_key").FromVault("vault://secret/data/ci#API_KEY")
+
+	values, err := p.ResolveSecrets(context.Background(), fakeSecretProvider{
+		"env:DB_PASS":                    "hunter2",
+		"vault://secret/data/ci#API_KEY": "abc123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["db_pass"] != "hunter2" || values["api_key"] != "abc123" {
+		t.Errorf("unexpected resolved secrets: %+v", values)
+	}
+}
+
+func TestResolveSecretsRequiresSource(t *testing.T) {
+	p := New()
+	p.Secret("unset")
+
+	if _, err := p.ResolveSecrets(context.Background(), secrets.Provider(fakeSecretProvider{})); err == nil {
+		t.Error("expected error for a secret with no source configured")
+	}
+}
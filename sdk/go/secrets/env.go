@@ -0,0 +1,23 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvProvider resolves secrets from the runner's own environment, matching
+// sykli.SecretResource.FromEnv.
+type EnvProvider struct{}
+
+// Resolve reads ref - an env var name, optionally prefixed with "env:" as
+// sykli's pipeline JSON wraps it - from the process environment.
+func (EnvProvider) Resolve(ctx context.Context, ref string) (string, map[string]string, error) {
+	name := strings.TrimPrefix(ref, "env:")
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", nil, fmt.Errorf("secrets: environment variable %q is not set", name)
+	}
+	return value, nil, nil
+}
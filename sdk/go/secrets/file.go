@@ -0,0 +1,23 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FileProvider resolves secrets by reading a file from the runner's
+// filesystem, matching sykli.SecretResource.FromFile.
+type FileProvider struct{}
+
+// Resolve reads the file at ref and returns its contents, trimming a single
+// trailing newline the way most secret-mounting tools (Docker Swarm,
+// Kubernetes, Vault agent) write secret files.
+func (FileProvider) Resolve(ctx context.Context, ref string) (string, map[string]string, error) {
+	raw, err := os.ReadFile(ref)
+	if err != nil {
+		return "", nil, fmt.Errorf("secrets: reading %q: %w", ref, err)
+	}
+	return strings.TrimSuffix(string(raw), "\n"), nil, nil
+}
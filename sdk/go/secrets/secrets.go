@@ -0,0 +1,96 @@
+// Package secrets defines the provider interface runners use to resolve the
+// sykli.SecretResource entries a pipeline declares. The SDK only emits each
+// secret's source - an "env:" variable name, a file path, or a "vault://"
+// reference - into the pipeline's "resources" map; the actual value is
+// fetched by the runner at task-launch time, the same separation package
+// artifacts draws between store configuration (emitted by the SDK) and the
+// actual upload/download (performed by the runner).
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Provider resolves a secret reference - the string sykli.SecretResource
+// emits into the pipeline's "resources" map - to its value. meta carries
+// provider-specific, non-sensitive detail (e.g. a Vault lease ID) that
+// callers may log or use to track renewal; it is never the secret value
+// itself.
+type Provider interface {
+	Resolve(ctx context.Context, ref string) (value string, meta map[string]string, err error)
+}
+
+// New returns a Provider that dispatches each ref to EnvProvider,
+// FileProvider, or vault by prefix - "env:" for environment variables,
+// "vault://" for Vault, and anything else treated as a file path -
+// mirroring the prefixes sykli.SecretResource's wire format already uses.
+// vault may be nil if the pipeline has no vault-sourced secrets.
+func New(vault *VaultProvider) Provider {
+	return &dispatcher{vault: vault}
+}
+
+type dispatcher struct {
+	vault *VaultProvider
+}
+
+func (d *dispatcher) Resolve(ctx context.Context, ref string) (string, map[string]string, error) {
+	switch {
+	case strings.HasPrefix(ref, "env:"):
+		return EnvProvider{}.Resolve(ctx, ref)
+	case strings.HasPrefix(ref, "vault://"):
+		if d.vault == nil {
+			return "", nil, fmt.Errorf("secrets: ref %q needs a vault provider, none configured", ref)
+		}
+		return d.vault.Resolve(ctx, ref)
+	default:
+		return FileProvider{}.Resolve(ctx, ref)
+	}
+}
+
+// Masker wraps an io.Writer, replacing any registered secret value with
+// "***" before writing through. Wrap a runner's log writer with one so
+// resolved secret values never reach CI logs verbatim.
+type Masker struct {
+	w  io.Writer
+	mu sync.RWMutex
+	v  []string
+}
+
+// NewMasker returns a Masker that writes through to w.
+func NewMasker(w io.Writer) *Masker {
+	return &Masker{w: w}
+}
+
+// Register adds values to redact from subsequent writes. Safe to call
+// concurrently with Write - e.g. as each secret resolves on its own
+// goroutine.
+func (m *Masker) Register(values ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, v := range values {
+		if v != "" {
+			m.v = append(m.v, v)
+		}
+	}
+}
+
+// Write redacts any registered secret value found in p before writing the
+// result through to the underlying writer.
+func (m *Masker) Write(p []byte) (int, error) {
+	m.mu.RLock()
+	values := append([]string(nil), m.v...)
+	m.mu.RUnlock()
+
+	out := string(p)
+	for _, v := range values {
+		out = strings.ReplaceAll(out, v, "***")
+	}
+	if _, err := io.WriteString(m.w, out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
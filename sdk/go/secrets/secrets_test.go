@@ -0,0 +1,56 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+)
+
+func TestDispatcherRoutesEnvRef(t *testing.T) {
+	os.Setenv("SECRETS_TEST_VAR", "s3kr1t")
+	defer os.Unsetenv("SECRETS_TEST_VAR")
+
+	value, _, err := New(nil).Resolve(context.Background(), "env:SECRETS_TEST_VAR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "s3kr1t" {
+		t.Errorf("expected s3kr1t, got %q", value)
+	}
+}
+
+func TestDispatcherRoutesFileRef(t *testing.T) {
+	path := t.TempDir() + "/secret"
+	if err := os.WriteFile(path, []byte("file-value\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	value, _, err := New(nil).Resolve(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "file-value" {
+		t.Errorf("expected file-value, got %q", value)
+	}
+}
+
+func TestDispatcherVaultRefWithoutProviderErrors(t *testing.T) {
+	_, _, err := New(nil).Resolve(context.Background(), "vault://secret/data/ci#KEY")
+	if err == nil {
+		t.Error("expected error resolving a vault ref with no vault provider configured")
+	}
+}
+
+func TestMaskerRedactsRegisteredValues(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewMasker(&buf)
+	m.Register("s3kr1t", "")
+
+	if _, err := m.Write([]byte("login with s3kr1t now")); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "login with *** now" {
+		t.Errorf("expected redacted output, got %q", got)
+	}
+}
@@ -0,0 +1,230 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VaultAuth configures how a VaultProvider authenticates. Set Token for a
+// pre-issued token, or RoleID/SecretID to have the provider log in via
+// AppRole on first use.
+type VaultAuth struct {
+	Token string
+
+	RoleID   string
+	SecretID string
+}
+
+// VaultProvider resolves "vault://<mount>/<path>#<key>" refs - e.g.
+// "vault://secret/data/ci#DB_PASSWORD" - against a HashiCorp Vault KV v2
+// secrets engine. Leases returned by Resolve are renewed on a background
+// goroutine for as long as the provider is alive; call Close to stop it.
+type VaultProvider struct {
+	addr   string
+	auth   VaultAuth
+	client *http.Client
+
+	mu          sync.Mutex
+	token       string
+	renewCancel context.CancelFunc
+}
+
+// NewVaultProvider returns a VaultProvider against the given Vault server
+// address, e.g. "https://vault.internal:8200".
+func NewVaultProvider(addr string, auth VaultAuth) *VaultProvider {
+	return &VaultProvider{
+		addr:   strings.TrimSuffix(addr, "/"),
+		auth:   auth,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Resolve fetches the value named by ref's fragment from the KV v2 secret
+// at ref's mount path, authenticating on first use and renewing the
+// resulting lease in the background.
+func (p *VaultProvider) Resolve(ctx context.Context, ref string) (string, map[string]string, error) {
+	mountPath, key, err := parseVaultRef(ref)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token, err := p.ensureToken(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var out vaultKVv2Response
+	if err := p.do(ctx, http.MethodGet, "/v1/"+mountPath, nil, token, &out); err != nil {
+		return "", nil, fmt.Errorf("secrets: vault read %q: %w", mountPath, err)
+	}
+
+	value, ok := out.Data.Data[key]
+	if !ok {
+		return "", nil, fmt.Errorf("secrets: vault secret %q has no key %q", mountPath, key)
+	}
+
+	meta := map[string]string{"lease_id": out.LeaseID}
+	if out.LeaseID != "" && out.LeaseDuration > 0 {
+		p.startRenewal(out.LeaseID, time.Duration(out.LeaseDuration)*time.Second)
+	}
+	return value, meta, nil
+}
+
+// Close stops the background lease-renewal goroutine, if one is running.
+func (p *VaultProvider) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.renewCancel != nil {
+		p.renewCancel()
+		p.renewCancel = nil
+	}
+}
+
+// ensureToken returns the provider's Vault token, logging in via AppRole on
+// first use if only RoleID/SecretID were configured.
+func (p *VaultProvider) ensureToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" {
+		return p.token, nil
+	}
+	if p.auth.Token != "" {
+		p.token = p.auth.Token
+		return p.token, nil
+	}
+	if p.auth.RoleID == "" || p.auth.SecretID == "" {
+		return "", fmt.Errorf("secrets: vault auth needs Token or RoleID+SecretID")
+	}
+
+	var out vaultAppRoleLoginResponse
+	body := map[string]string{"role_id": p.auth.RoleID, "secret_id": p.auth.SecretID}
+	if err := p.do(ctx, http.MethodPost, "/v1/auth/approle/login", body, "", &out); err != nil {
+		return "", fmt.Errorf("secrets: vault AppRole login: %w", err)
+	}
+	p.token = out.Auth.ClientToken
+	return p.token, nil
+}
+
+// startRenewal renews leaseID at 70% of its TTL - a safety margin before
+// expiry - until Close is called or a renewal attempt fails. A later call
+// replaces any renewal loop already running, since a fresh Resolve means a
+// fresh lease superseding the old one.
+func (p *VaultProvider) startRenewal(leaseID string, ttl time.Duration) {
+	p.mu.Lock()
+	if p.renewCancel != nil {
+		p.renewCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.renewCancel = cancel
+	p.mu.Unlock()
+
+	go p.renewLoop(ctx, leaseID, ttl)
+}
+
+func (p *VaultProvider) renewLoop(ctx context.Context, leaseID string, ttl time.Duration) {
+	interval := ttl * 7 / 10
+	if interval <= 0 {
+		interval = ttl
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.renew(ctx, leaseID); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (p *VaultProvider) renew(ctx context.Context, leaseID string) error {
+	p.mu.Lock()
+	token := p.token
+	p.mu.Unlock()
+
+	body := map[string]string{"lease_id": leaseID}
+	return p.do(ctx, http.MethodPut, "/v1/sys/leases/renew", body, token, nil)
+}
+
+// do issues a Vault HTTP API request and decodes a JSON response into out,
+// if non-nil.
+func (p *VaultProvider) do(ctx context.Context, method, path string, body interface{}, token string, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.addr+path, reader)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("vault returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// parseVaultRef splits a "vault://<mount path>#<key>" ref into the KV v2
+// API path and the key within its data map.
+func parseVaultRef(ref string) (mountPath, key string, err error) {
+	const scheme = "vault://"
+	if !strings.HasPrefix(ref, scheme) {
+		return "", "", fmt.Errorf("secrets: not a vault ref: %q", ref)
+	}
+	mountPath, key, ok := strings.Cut(strings.TrimPrefix(ref, scheme), "#")
+	if !ok || mountPath == "" || key == "" {
+		return "", "", fmt.Errorf("secrets: vault ref %q must look like \"vault://<mount>/<path>#<key>\"", ref)
+	}
+	return mountPath, key, nil
+}
+
+// vaultKVv2Response is the subset of a Vault KV v2 read response this
+// package uses.
+type vaultKVv2Response struct {
+	LeaseID       string `json:"lease_id"`
+	LeaseDuration int    `json:"lease_duration"`
+	Data          struct {
+		Data map[string]string `json:"data"`
+	} `json:"data"`
+}
+
+// vaultAppRoleLoginResponse is the subset of a Vault AppRole login response
+// this package uses.
+type vaultAppRoleLoginResponse struct {
+	Auth struct {
+		ClientToken string `json:"client_token"`
+	} `json:"auth"`
+}
@@ -0,0 +1,148 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseVaultRef(t *testing.T) {
+	mount, key, err := parseVaultRef("vault://secret/data/ci#DB_PASSWORD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mount != "secret/data/ci" || key != "DB_PASSWORD" {
+		t.Errorf("unexpected parse: mount=%q key=%q", mount, key)
+	}
+}
+
+func TestParseVaultRefRejectsMissingKey(t *testing.T) {
+	if _, _, err := parseVaultRef("vault://secret/data/ci"); err == nil {
+		t.Error("expected error for ref without a #key fragment")
+	}
+}
+
+func TestParseVaultRefRejectsWrongScheme(t *testing.T) {
+	if _, _, err := parseVaultRef("secret/data/ci#DB_PASSWORD"); err == nil {
+		t.Error("expected error for ref missing the vault:// scheme")
+	}
+}
+
+func TestVaultProviderResolveWithToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			t.Errorf("expected token header, got %q", r.Header.Get("X-Vault-Token"))
+		}
+		if r.URL.Path != "/v1/secret/data/ci" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(vaultKVv2Response{
+			LeaseID:       "lease-1",
+			LeaseDuration: 0, // no renewal, so the test doesn't leave a goroutine running
+			Data: struct {
+				Data map[string]string `json:"data"`
+			}{Data: map[string]string{"DB_PASSWORD": "hunter2"}},
+		})
+	}))
+	defer srv.Close()
+
+	p := NewVaultProvider(srv.URL, VaultAuth{Token: "test-token"})
+	value, meta, err := p.Resolve(context.Background(), "vault://secret/data/ci#DB_PASSWORD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "hunter2" {
+		t.Errorf("expected hunter2, got %q", value)
+	}
+	if meta["lease_id"] != "lease-1" {
+		t.Errorf("expected lease_id in meta, got %v", meta)
+	}
+}
+
+func TestVaultProviderResolveMissingKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(vaultKVv2Response{})
+	}))
+	defer srv.Close()
+
+	p := NewVaultProvider(srv.URL, VaultAuth{Token: "test-token"})
+	if _, _, err := p.Resolve(context.Background(), "vault://secret/data/ci#MISSING"); err == nil {
+		t.Error("expected error for a key absent from the secret's data")
+	}
+}
+
+func TestVaultProviderAppRoleLogin(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			json.NewEncoder(w).Encode(vaultAppRoleLoginResponse{
+				Auth: struct {
+					ClientToken string `json:"client_token"`
+				}{ClientToken: "approle-token"},
+			})
+		case "/v1/secret/data/ci":
+			if r.Header.Get("X-Vault-Token") != "approle-token" {
+				t.Errorf("expected AppRole-issued token, got %q", r.Header.Get("X-Vault-Token"))
+			}
+			json.NewEncoder(w).Encode(vaultKVv2Response{
+				Data: struct {
+					Data map[string]string `json:"data"`
+				}{Data: map[string]string{"KEY": "value"}},
+			})
+		default:
+			t.Fatalf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewVaultProvider(srv.URL, VaultAuth{RoleID: "role", SecretID: "secret"})
+	value, _, err := p.Resolve(context.Background(), "vault://secret/data/ci#KEY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "value" {
+		t.Errorf("expected value, got %q", value)
+	}
+}
+
+func TestVaultProviderRenewsLeaseInBackground(t *testing.T) {
+	renewed := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/secret/data/ci":
+			json.NewEncoder(w).Encode(vaultKVv2Response{
+				LeaseID:       "lease-1",
+				LeaseDuration: 1,
+				Data: struct {
+					Data map[string]string `json:"data"`
+				}{Data: map[string]string{"KEY": "value"}},
+			})
+		case "/v1/sys/leases/renew":
+			select {
+			case renewed <- struct{}{}:
+			default:
+			}
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(map[string]string{})
+		default:
+			t.Fatalf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewVaultProvider(srv.URL, VaultAuth{Token: "test-token"})
+	defer p.Close()
+
+	if _, _, err := p.Resolve(context.Background(), "vault://secret/data/ci#KEY"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-renewed:
+	case <-time.After(2 * time.Second):
+		t.Error("expected lease renewal request within the lease's TTL")
+	}
+}
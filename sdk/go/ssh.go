@@ -0,0 +1,72 @@
+package sykli
+
+// =============================================================================
+// SSH AGENT FORWARDING
+// =============================================================================
+
+// SSHResource represents a forwarded SSH agent socket or private key,
+// mirroring BuildKit's `--mount=type=ssh` support. Mount it into a task
+// with MountSSH to forward credentials without baking keys into images.
+type SSHResource struct {
+	pipeline *Pipeline
+	name     string
+	keyPath  string // set by FromKey; empty means forward the agent socket
+}
+
+// SSH registers a named SSH forwarding resource. By default it forwards the
+// host's SSH agent (SSH_AUTH_SOCK) - call FromKey to forward a specific
+// private key file instead.
+func (p *Pipeline) SSH(name string) *SSHResource {
+	if name == "" {
+		log.Panic().Msg("ssh resource name cannot be empty")
+	}
+	s := &SSHResource{pipeline: p, name: name}
+	log.Debug().Str("name", name).Msg("registered ssh resource")
+	p.sshResources = append(p.sshResources, s)
+	return s
+}
+
+// FromAgent forwards the host's running SSH agent. This is the default, so
+// calling it is only useful for readability at call sites.
+func (s *SSHResource) FromAgent() *SSHResource {
+	s.keyPath = ""
+	return s
+}
+
+// FromKey forwards a specific private key file instead of the agent socket.
+func (s *SSHResource) FromKey(path string) *SSHResource {
+	if path == "" {
+		log.Panic().Str("ssh", s.name).Msg("key path cannot be empty")
+	}
+	s.keyPath = path
+	return s
+}
+
+// ID returns a unique identifier for this SSH resource.
+func (s *SSHResource) ID() string {
+	return "ssh:" + s.name
+}
+
+// MountSSH mounts an SSH agent socket or key into the container at path.
+// Unless the task already sets SSH_AUTH_SOCK explicitly, it is set to path
+// automatically so `git clone git@...` and friends work out of the box.
+func (t *Task) MountSSH(ssh *SSHResource, path string) *Task {
+	if ssh == nil {
+		log.Panic().Str("task", t.name).Msg("ssh resource cannot be nil")
+	}
+	if path == "" || path[0] != '/' {
+		log.Panic().Str("task", t.name).Str("path", path).Msg("mount path must be absolute (start with /)")
+	}
+	t.mounts = append(t.mounts, Mount{
+		resource:  ssh.ID(),
+		path:      path,
+		mountType: "ssh",
+	})
+	if _, set := t.env["SSH_AUTH_SOCK"]; !set {
+		if t.env == nil {
+			t.env = make(map[string]string)
+		}
+		t.env["SSH_AUTH_SOCK"] = path
+	}
+	return t
+}
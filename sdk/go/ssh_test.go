@@ -0,0 +1,90 @@
+package sykli
+
+import "testing"
+
+func TestSSHResourceID(t *testing.T) {
+	p := New()
+	ssh := p.SSH("default")
+
+	if ssh.ID() != "ssh:default" {
+		t.Errorf("expected ID 'ssh:default', got %q", ssh.ID())
+	}
+}
+
+func TestMountSSHSetsAuthSockEnv(t *testing.T) {
+	p := New()
+	ssh := p.SSH("default")
+	p.Task("build").Container("golang:1.21").MountSSH(ssh, "/ssh-agent.sock").Run("git clone git@example.com:x")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := result["tasks"].([]interface{})[0].(map[string]interface{})
+
+	mounts := task["mounts"].([]interface{})
+	mount := mounts[0].(map[string]interface{})
+	if mount["type"] != "ssh" || mount["resource"] != "ssh:default" {
+		t.Errorf("unexpected ssh mount: %+v", mount)
+	}
+
+	env := task["env"].(map[string]interface{})
+	if env["SSH_AUTH_SOCK"] != "/ssh-agent.sock" {
+		t.Errorf("expected SSH_AUTH_SOCK='/ssh-agent.sock', got %v", env["SSH_AUTH_SOCK"])
+	}
+}
+
+func TestMountSSHDoesNotOverrideExplicitEnv(t *testing.T) {
+	p := New()
+	ssh := p.SSH("default")
+	p.Task("build").
+		Container("golang:1.21").
+		Env("SSH_AUTH_SOCK", "/custom.sock").
+		MountSSH(ssh, "/ssh-agent.sock").
+		Run("git clone git@example.com:x")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := result["tasks"].([]interface{})[0].(map[string]interface{})["env"].(map[string]interface{})
+	if env["SSH_AUTH_SOCK"] != "/custom.sock" {
+		t.Errorf("expected explicit SSH_AUTH_SOCK to be preserved, got %v", env["SSH_AUTH_SOCK"])
+	}
+}
+
+func TestSSHFromKeyResourceEmitted(t *testing.T) {
+	p := New()
+	ssh := p.SSH("deploy").FromKey("/home/ci/.ssh/id_ed25519")
+	p.Task("build").Container("golang:1.21").MountSSH(ssh, "/ssh-agent.sock").Run("true")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources := result["resources"].(map[string]interface{})
+	res := resources["ssh:deploy"].(map[string]interface{})
+	if res["type"] != "ssh" || res["path"] != "/home/ci/.ssh/id_ed25519" {
+		t.Errorf("unexpected ssh resource: %+v", res)
+	}
+}
+
+func TestEmptySSHNamePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty ssh resource name")
+		}
+	}()
+	p := New()
+	p.SSH("")
+}
+
+func TestNilSSHMountPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for nil ssh resource")
+		}
+	}()
+	p := New()
+	p.Task("test").MountSSH(nil, "/ssh-agent.sock")
+}
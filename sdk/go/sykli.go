@@ -30,10 +30,11 @@
 package sykli
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -65,10 +66,23 @@ func init() {
 
 // Pipeline represents a CI pipeline with tasks and resources.
 type Pipeline struct {
-	tasks     []*Task
-	dirs      []*Directory
-	caches    []*CacheVolume
-	templates map[string]*Template
+	tasks           []*Task
+	dirs            []*Directory
+	caches          []*CacheVolume
+	templates       map[string]*Template
+	groups          []*TaskGroup
+	sshResources    []*SSHResource
+	secretResources []*SecretResource
+	autoCancel      *AutoCancelPolicy
+	concurrency     *ConcurrencyPolicy
+	finallyTasks    []*Task
+	artifactStore   *ArtifactStore
+	registryAuths   []*DockerRegistryAuth
+	// emitFormat and schemaVersion are set by EmitFormat/EmitVersion; see
+	// emit_format.go. Zero values mean "auto-detect", matching EmitTo's
+	// long-standing default behavior.
+	emitFormat    EmitFormat
+	schemaVersion string
 }
 
 // New creates a new pipeline.
@@ -122,6 +136,7 @@ func (d *Directory) ID() string {
 type CacheVolume struct {
 	pipeline *Pipeline
 	name     string
+	sharing  CacheSharing
 }
 
 // Cache creates a named cache volume.
@@ -143,6 +158,31 @@ func (c *CacheVolume) ID() string {
 	return c.name
 }
 
+// CacheSharing controls how a cache volume may be shared across concurrent
+// mounts, mirroring BuildKit's `--mount=type=cache,sharing=...`.
+type CacheSharing string
+
+const (
+	// CacheShared allows multiple tasks to mount the cache concurrently.
+	CacheShared CacheSharing = "shared"
+	// CachePrivate gives each mounting task its own copy-on-write view.
+	CachePrivate CacheSharing = "private"
+	// CacheLocked serializes access - only one task may hold the mount at a time.
+	CacheLocked CacheSharing = "locked"
+)
+
+// Sharing sets the sharing mode for this cache volume. Defaults to
+// CacheShared when never called.
+func (c *CacheVolume) Sharing(mode CacheSharing) *CacheVolume {
+	switch mode {
+	case CacheShared, CachePrivate, CacheLocked:
+	default:
+		log.Panic().Str("cache", c.name).Str("sharing", string(mode)).Msg("invalid cache sharing mode")
+	}
+	c.sharing = mode
+	return c
+}
+
 // =============================================================================
 // TEMPLATE
 // =============================================================================
@@ -157,6 +197,9 @@ type Template struct {
 	workdir   string
 	env       map[string]string
 	mounts    []Mount
+	// extends holds parent templates this one inherits from, applied before
+	// this template's own settings (see Extends).
+	extends []*Template
 }
 
 // Template creates a new reusable task template.
@@ -220,18 +263,14 @@ func (t *Template) Mount(dir *Directory, path string) *Template {
 }
 
 // MountCache adds a cache mount for tasks using this template.
-func (t *Template) MountCache(cache *CacheVolume, path string) *Template {
+func (t *Template) MountCache(cache *CacheVolume, path string, opts ...MountOption) *Template {
 	if cache == nil {
 		log.Panic().Str("template", t.name).Msg("cache cannot be nil")
 	}
 	if path == "" || path[0] != '/' {
 		log.Panic().Str("template", t.name).Str("path", path).Msg("mount path must be absolute")
 	}
-	t.mounts = append(t.mounts, Mount{
-		resource:  cache.ID(),
-		path:      path,
-		mountType: "cache",
-	})
+	t.mounts = append(t.mounts, newCacheMount(cache, path, opts))
 	return t
 }
 
@@ -249,6 +288,45 @@ type Mount struct {
 	mountType string
 	// sourcePath is the host path for directories (not used for caches)
 	sourcePath string
+	// cacheName is set for cache mounts so parallel-group validation can
+	// spot two tasks locking the same cache volume concurrently.
+	cacheName string
+	// sharing is the cache's sharing mode, copied in at mount time.
+	sharing CacheSharing
+	// uid/gid/mode/readOnly are BuildKit-style ownership knobs for the mount.
+	uid, gid int
+	hasOwner bool
+	mode     string
+	readOnly bool
+	// secretRequired is set by secret mounts created with SecretRequired().
+	secretRequired bool
+}
+
+// MountOption customizes a cache mount's ownership and access knobs.
+type MountOption func(*Mount)
+
+// WithOwner sets the uid/gid that should own the mounted cache directory.
+func WithOwner(uid, gid int) MountOption {
+	return func(m *Mount) {
+		m.uid, m.gid, m.hasOwner = uid, gid, true
+	}
+}
+
+// WithMode sets the octal permission mode of the mounted cache directory (e.g. 0755).
+func WithMode(mode int) MountOption {
+	if mode < 0 || mode > 0o777 {
+		log.Panic().Int("mode", mode).Msg("invalid mount mode")
+	}
+	return func(m *Mount) {
+		m.mode = fmt.Sprintf("%04o", mode)
+	}
+}
+
+// WithReadOnly mounts the cache volume read-only.
+func WithReadOnly() MountOption {
+	return func(m *Mount) {
+		m.readOnly = true
+	}
 }
 
 // Service represents a service container that runs alongside a task.
@@ -266,23 +344,48 @@ type TaskInput struct {
 
 // Task represents a single task in the pipeline.
 type Task struct {
-	pipeline   *Pipeline
-	name       string
-	command    string
-	container  string
-	workdir    string
-	env        map[string]string
-	mounts     []Mount
-	inputs     []string      // v1-style input file patterns
-	taskInputs []TaskInput   // v2-style inputs from other tasks
-	outputs    map[string]string
-	dependsOn  []string
-	when       string
-	secrets    []string
-	matrix     map[string][]string
-	services   []Service
-	retry      int
-	timeout    int // seconds
+	pipeline      *Pipeline
+	name          string
+	command       string
+	container     string
+	workdir       string
+	env           map[string]string
+	mounts        []Mount
+	inputs        []string    // v1-style input file patterns
+	taskInputs    []TaskInput // v2-style inputs from other tasks
+	outputs       map[string]string
+	dependsOn     []string
+	when          string
+	secrets       []string
+	matrix        map[string][]string
+	services      []Service
+	retry         int
+	timeout       int // seconds
+	nonCancelable bool
+	runsOn        []string
+	isFinally     bool
+	references    []TaskReference
+	matrixExclude []map[string]string
+	matrixInclude []map[string]string
+	matrixExpand  bool
+	whenAST       WhenNode
+	targetName    string
+	watchSrc      []string
+	watchDebounce int // milliseconds
+	requires      []Capability
+	groupName     string // set when this task is a member of a Parallel group
+
+	// inputDiscoverers are run at cache-key time by cacheKeyFor; see
+	// InputsFrom. Like t.inputs, these have no JSON wire representation -
+	// they're resolved to plain file paths before a cache_key is ever
+	// emitted, so a consumer of the emitted pipeline never needs to know
+	// discovery happened.
+	inputDiscoverers []func() ([]string, error)
+
+	skipReason    string // set by Skip; Executor.runOne short-circuits on this
+	noCacheReason string // set by NoCache; Executor.Run never assigns this task an actionID
+
+	k8sOptions *K8sTaskOptions // set by Task.K8s; read by the k8s/tekton targets
 }
 
 // Task creates a new task with the given name.
@@ -307,34 +410,34 @@ func (p *Pipeline) Task(name string) *Task {
 	return t
 }
 
-// From applies a template's configuration to this task.
-// Template settings are applied first, then task-specific settings override them.
-func (t *Task) From(tmpl *Template) *Task {
-	if tmpl == nil {
+// From applies one or more templates' configuration to this task, in the
+// given order: later templates override earlier ones, and task-level
+// settings (set before or after From) always win. Each template's own
+// Extends() chain is resolved and applied ahead of the template itself, so
+// From(base, overlay) expands to base's ancestors, base, overlay's
+// ancestors, overlay, task.
+func (t *Task) From(tmpls ...*Template) *Task {
+	if len(tmpls) == 0 {
 		log.Panic().Str("task", t.name).Msg("template cannot be nil")
 	}
-
-	// Apply template settings (task settings will override these)
-	if tmpl.container != "" && t.container == "" {
-		t.container = tmpl.container
-	}
-	if tmpl.workdir != "" && t.workdir == "" {
-		t.workdir = tmpl.workdir
-	}
-
-	// Merge env: template first, then task overrides
-	for k, v := range tmpl.env {
-		if _, exists := t.env[k]; !exists {
-			t.env[k] = v
+	for _, tmpl := range tmpls {
+		if tmpl == nil {
+			log.Panic().Str("task", t.name).Msg("template cannot be nil")
 		}
 	}
 
-	// Prepend template mounts (task mounts come after)
-	if len(tmpl.mounts) > 0 {
-		t.mounts = append(tmpl.mounts, t.mounts...)
+	chain, err := resolveTemplateChain(tmpls)
+	if err != nil {
+		log.Panic().Str("task", t.name).Err(err).Msg("From: could not resolve template chain")
 	}
 
-	log.Debug().Str("task", t.name).Str("template", tmpl.name).Msg("applied template")
+	applyTemplate(t, mergeTemplateChain(chain))
+
+	names := make([]string, len(chain))
+	for i, tmpl := range chain {
+		names[i] = tmpl.name
+	}
+	log.Debug().Str("task", t.name).Strs("templates", names).Msg("applied template chain")
 	return t
 }
 
@@ -379,19 +482,36 @@ func (t *Task) Mount(dir *Directory, path string) *Task {
 }
 
 // MountCache mounts a cache volume into the container.
-func (t *Task) MountCache(cache *CacheVolume, path string) *Task {
+func (t *Task) MountCache(cache *CacheVolume, path string, opts ...MountOption) *Task {
 	if cache == nil {
 		log.Panic().Str("task", t.name).Msg("cache cannot be nil")
 	}
 	if path == "" || path[0] != '/' {
 		log.Panic().Str("task", t.name).Str("path", path).Msg("mount path must be absolute (start with /)")
 	}
-	t.mounts = append(t.mounts, Mount{
+	t.mounts = append(t.mounts, newCacheMount(cache, path, opts))
+	return t
+}
+
+// newCacheMount builds a cache Mount from a CacheVolume, path, and option list,
+// validating the octal mode string if one was provided.
+func newCacheMount(cache *CacheVolume, path string, opts []MountOption) Mount {
+	m := Mount{
 		resource:  cache.ID(),
 		path:      path,
 		mountType: "cache",
-	})
-	return t
+		cacheName: cache.ID(),
+		sharing:   cache.sharing,
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	if m.mode != "" {
+		if _, err := strconv.ParseUint(m.mode, 8, 32); err != nil {
+			log.Panic().Str("cache", cache.ID()).Str("mode", m.mode).Msg("mount mode must be a valid octal permission")
+		}
+	}
+	return m
 }
 
 // Workdir sets the working directory inside the container.
@@ -415,6 +535,17 @@ func (t *Task) Inputs(patterns ...string) *Task {
 	return t
 }
 
+// InputsFrom registers a dynamic input discoverer: fn runs once, when
+// this task's cache key is computed, and the paths it returns are
+// hashed alongside anything from Inputs. This is the escape hatch behind
+// GoPackage and PythonModule for callers with their own discovery
+// logic - return the exact set of files the task actually depends on,
+// rather than an over-broad glob that invalidates on unrelated changes.
+func (t *Task) InputsFrom(fn func() ([]string, error)) *Task {
+	t.inputDiscoverers = append(t.inputDiscoverers, fn)
+	return t
+}
+
 // Output sets a named output path.
 func (t *Task) Output(name, path string) *Task {
 	if name == "" || path == "" {
@@ -484,18 +615,40 @@ func (t *Task) After(tasks ...string) *Task {
 	return t
 }
 
-// When sets a condition for when this task should run.
-// The condition is evaluated at runtime based on CI context variables:
+// When sets a condition for when this task should run. The condition is
+// parsed into an AST immediately - a malformed expression panics here
+// rather than failing at runtime in the runner. It's evaluated against CI
+// context variables:
 //   - branch == 'main' - run only on main branch
 //   - branch != 'main' - run on all branches except main
-//   - tag != '' - run only when a tag is present
+//   - tag != ” - run only when a tag is present
 //   - event == 'push' - run only on push events
 //   - ci == true - run only in CI environment
+//   - env.FOO == 'bar' - run based on a CI environment variable
+//
+// Use WhenExpr instead to build the condition from OnBranch/OnTag/OnEvent
+// and their And/Or/Not combinators rather than a raw string.
 func (t *Task) When(condition string) *Task {
 	if condition == "" {
 		log.Panic().Str("task", t.name).Msg("condition cannot be empty")
 	}
+	ast, err := parseWhen(condition)
+	if err != nil {
+		log.Panic().Str("task", t.name).Str("condition", condition).Err(err).Msg("invalid When condition")
+	}
 	t.when = condition
+	t.whenAST = ast
+	return t
+}
+
+// WhenExpr sets a condition built from OnBranch/OnTag/OnEvent and their
+// And/Or/Not combinators instead of a raw string.
+func (t *Task) WhenExpr(expr *WhenExpr) *Task {
+	if expr == nil {
+		log.Panic().Str("task", t.name).Msg("WhenExpr: expr cannot be nil")
+	}
+	t.when = expr.source
+	t.whenAST = expr.ast
 	return t
 }
 
@@ -524,6 +677,9 @@ func (t *Task) Secrets(names ...string) *Task {
 // Matrix builds run the task multiple times with different parameter combinations.
 // Each dimension's values are exposed as environment variables.
 func (t *Task) Matrix(key string, values ...string) *Task {
+	if t.isFinally {
+		log.Panic().Str("task", t.name).Msg("Matrix is not supported on Finally tasks")
+	}
 	if key == "" {
 		log.Panic().Str("task", t.name).Msg("matrix key cannot be empty")
 	}
@@ -569,6 +725,68 @@ func (t *Task) Timeout(seconds int) *Task {
 	return t
 }
 
+// Target pins this task to a named target, e.g. for pipelines that run
+// some tasks on Kubernetes and others on Podman. The name is opaque to
+// the SDK - it's matched against whatever an Executor's
+// WithTargetConcurrency limits are keyed on, and otherwise just carried
+// through to the compiled pipeline for the thing that dispatches tasks
+// to targets to read.
+func (t *Task) Target(name string) *Task {
+	if name == "" {
+		log.Panic().Str("task", t.name).Msg("target name cannot be empty")
+	}
+	t.targetName = name
+	return t
+}
+
+// Src declares file glob patterns that should rerun this task (and its
+// downstream DAG) under `sykli watch` when a matching file changes.
+// Unlike Inputs, Src doesn't feed the cache key - it's purely a rerun
+// trigger. Multiple calls union their globs.
+func (t *Task) Src(globs ...string) *Task {
+	t.watchSrc = append(t.watchSrc, globs...)
+	return t
+}
+
+// Debounce sets how long, in milliseconds, the watcher waits after the
+// last matching file-change event before rerunning this task - long
+// enough to collapse a burst of saves (e.g. a formatter rewriting
+// several files) into a single rerun.
+func (t *Task) Debounce(ms int) *Task {
+	if ms < 0 {
+		log.Panic().Str("task", t.name).Int("debounce_ms", ms).Msg("debounce must not be negative")
+	}
+	t.watchDebounce = ms
+	return t
+}
+
+// Skip marks this task as intentionally not run, recording why so a
+// --why-style explanation (see Pipeline.Explain) or a runner's summary
+// table can show the reason instead of an unexplained absence. Unlike
+// When, which needs context (branch, env) only a runner has at dispatch
+// time, a skip has no such dependency, so Executor enforces it directly:
+// runOne returns a successful no-op Result without ever reaching target.
+func (t *Task) Skip(reason string) *Task {
+	if reason == "" {
+		log.Panic().Str("task", t.name).Msg("skip reason cannot be empty")
+	}
+	t.skipReason = reason
+	return t
+}
+
+// NoCache opts this task out of actioncache lookups and stores entirely,
+// documenting why - e.g. a command with a side effect Inputs() can't
+// capture - mirroring Gradle's @DisableCachingByDefault(because = "...").
+// Executor.Run never assigns a cache_key-derived actionID to a task with
+// a NoCache reason, so runOne always executes it via target.RunTask.
+func (t *Task) NoCache(reason string) *Task {
+	if reason == "" {
+		log.Panic().Str("task", t.name).Msg("no-cache reason cannot be empty")
+	}
+	t.noCacheReason = reason
+	return t
+}
+
 // =============================================================================
 // LANGUAGE PRESETS
 // =============================================================================
@@ -613,9 +831,11 @@ func (g *GoPreset) Build(output string) *Task {
 // TaskGroup represents a group of tasks that can be used as a dependency.
 // Created by Parallel() and can be passed to After() or Chain().
 type TaskGroup struct {
-	pipeline *Pipeline
-	name     string
-	tasks    []*Task
+	pipeline    *Pipeline
+	name        string
+	tasks       []*Task
+	onError     string // "" (stop on first failure) or "continue"
+	maxFailures int
 }
 
 // Chain creates a sequential dependency chain: a → b → c
@@ -633,11 +853,16 @@ func (p *Pipeline) Chain(items ...interface{}) {
 // Parallel creates a group of tasks that run concurrently.
 // Returns a TaskGroup that can be used as a dependency with After().
 func (p *Pipeline) Parallel(name string, tasks ...*Task) *TaskGroup {
-	return &TaskGroup{
+	g := &TaskGroup{
 		pipeline: p,
 		name:     name,
 		tasks:    tasks,
 	}
+	for _, t := range tasks {
+		t.groupName = name
+	}
+	p.groups = append(p.groups, g)
+	return g
 }
 
 // After makes all tasks in this group depend on the given tasks/groups.
@@ -650,6 +875,29 @@ func (g *TaskGroup) After(deps ...interface{}) *TaskGroup {
 	return g
 }
 
+// ContinueOnError lets every task in the group run to completion even if
+// a sibling fails, instead of the runner's default of short-circuiting
+// the group on the first failure. The runner is expected to collect
+// every failure into one MultiError (of per-task TaskError{Name,
+// ExitCode, Cause}) rather than stopping early - useful for a group of
+// independent lint/test checks where you want every failure reported in
+// one run.
+func (g *TaskGroup) ContinueOnError() *TaskGroup {
+	g.onError = "continue"
+	return g
+}
+
+// MaxFailures caps how many task failures this group tolerates under
+// ContinueOnError before the runner cancels the rest of the group
+// outright. n must be positive.
+func (g *TaskGroup) MaxFailures(n int) *TaskGroup {
+	if n <= 0 {
+		log.Panic().Str("group", g.name).Int("max_failures", n).Msg("MaxFailures must be positive")
+	}
+	g.maxFailures = n
+	return g
+}
+
 // TaskNames returns the names of all tasks in this group.
 // Used internally when this group is used as a dependency.
 func (g *TaskGroup) TaskNames() []string {
@@ -768,6 +1016,27 @@ func (p *Pipeline) reconstructCycle(from, to string, parent map[string]string) [
 	return cycle
 }
 
+// validateLockedCacheGroups rejects pipelines where two tasks in the same
+// Parallel(...) group both mount a "locked" cache - those tasks would
+// deadlock each other waiting for exclusive access to the same volume.
+func (p *Pipeline) validateLockedCacheGroups() error {
+	for _, g := range p.groups {
+		seen := make(map[string]string) // cacheName -> task that already claimed it
+		for _, t := range g.tasks {
+			for _, m := range t.mounts {
+				if m.mountType != "cache" || m.sharing != CacheLocked {
+					continue
+				}
+				if owner, ok := seen[m.cacheName]; ok {
+					return fmt.Errorf("cache %q is mounted as locked by both %q and %q in parallel group %q", m.cacheName, owner, t.name, g.name)
+				}
+				seen[m.cacheName] = t.name
+			}
+		}
+	}
+	return nil
+}
+
 // formatCyclePath formats a cycle as a readable string: a -> b -> c -> a
 func formatCyclePath(cycle []string) string {
 	if len(cycle) == 0 {
@@ -784,36 +1053,225 @@ func formatCyclePath(cycle []string) string {
 // EMIT
 // =============================================================================
 
-// Emit outputs the pipeline as JSON if --emit flag is present.
-// Call this at the end of your sykli.go file.
+// Emit outputs the pipeline if --emit is present on the command line,
+// honoring an optional --format=json|json-pretty|yaml and
+// --schema-version=v1|v2 alongside it. Call this at the end of your
+// sykli.go file.
 func (p *Pipeline) Emit() {
+	var doEmit bool
 	for _, arg := range os.Args[1:] {
-		if arg == "--emit" {
-			log.Debug().Int("tasks", len(p.tasks)).Msg("emitting pipeline")
-			if err := p.EmitTo(os.Stdout); err != nil {
-				log.Fatal().Err(err).Msg("failed to emit pipeline")
-			}
-			os.Exit(0)
+		switch {
+		case arg == "--emit":
+			doEmit = true
+		case strings.HasPrefix(arg, "--format="):
+			p.EmitFormat(parseEmitFormat(strings.TrimPrefix(arg, "--format=")))
+		case strings.HasPrefix(arg, "--schema-version="):
+			p.EmitVersion(strings.TrimPrefix(arg, "--schema-version="))
 		}
 	}
+	if !doEmit {
+		return
+	}
+
+	log.Debug().Int("tasks", len(p.tasks)).Str("format", p.emitFormat.String()).Msg("emitting pipeline")
+	if err := p.EmitTo(os.Stdout); err != nil {
+		log.Fatal().Err(err).Msg("failed to emit pipeline")
+	}
+	os.Exit(0)
+}
+
+// jsonMount is the wire shape of a Mount.
+type jsonMount struct {
+	Resource string `json:"resource"`
+	Path     string `json:"path"`
+	Type     string `json:"type"`
+	Sharing  string `json:"sharing,omitempty"`
+	UID      *int   `json:"uid,omitempty"`
+	GID      *int   `json:"gid,omitempty"`
+	Mode     string `json:"mode,omitempty"`
+	ReadOnly bool   `json:"readonly,omitempty"`
+	Required bool   `json:"required,omitempty"`
+}
+
+// jsonService is the wire shape of a Service.
+type jsonService struct {
+	Image string `json:"image"`
+	Name  string `json:"name"`
+}
+
+// jsonTaskInput is the wire shape of a TaskInput.
+type jsonTaskInput struct {
+	FromTask   string `json:"from_task"`
+	OutputName string `json:"output"`
+	DestPath   string `json:"dest"`
+	Key        string `json:"key,omitempty"`
+}
+
+// jsonTask is the wire shape of a Task, shared by the "tasks" and "finally"
+// arrays. References, OutputKeys, and WhenAST are derived fields - Parse
+// does not need to set them back, since rebuilding a Task from Command/
+// Env/Inputs and When lets EmitTo recompute them identically next time.
+type jsonTask struct {
+	Name          string                 `json:"name"`
+	Command       string                 `json:"command"`
+	Container     string                 `json:"container,omitempty"`
+	Workdir       string                 `json:"workdir,omitempty"`
+	Env           map[string]string      `json:"env,omitempty"`
+	Mounts        []jsonMount            `json:"mounts,omitempty"`
+	Inputs        []string               `json:"inputs,omitempty"`      // v1-style file patterns
+	TaskInputs    []jsonTaskInput        `json:"task_inputs,omitempty"` // v2-style inputs from other tasks
+	Outputs       map[string]string      `json:"outputs,omitempty"`
+	DependsOn     []string               `json:"depends_on,omitempty"`
+	When          string                 `json:"when,omitempty"`
+	Secrets       []string               `json:"secrets,omitempty"`
+	Matrix        map[string]interface{} `json:"matrix,omitempty"`
+	Services      []jsonService          `json:"services,omitempty"`
+	Retry         int                    `json:"retry,omitempty"`
+	Timeout       int                    `json:"timeout,omitempty"`
+	NonCancelable bool                   `json:"non_cancelable,omitempty"`
+	RunsOn        []string               `json:"runs_on,omitempty"`
+	References    []jsonReference        `json:"references,omitempty"`
+	OutputKeys    map[string]string      `json:"output_keys,omitempty"`
+	WhenAST       interface{}            `json:"when_ast,omitempty"`
+	CacheKey      string                 `json:"cache_key,omitempty"`
+	Target        string                 `json:"target,omitempty"`
+	Watch         *jsonWatch             `json:"watch,omitempty"`
+	Requires      []jsonCapability       `json:"requires,omitempty"`
+	Group         string                 `json:"group,omitempty"`
+	Skip          string                 `json:"skip,omitempty"`
+	NoCache       string                 `json:"no_cache,omitempty"`
+}
+
+// jsonWatch is the wire shape of a task's Src/Debounce watch-mode config.
+type jsonWatch struct {
+	Src        []string `json:"src,omitempty"`
+	DebounceMs int      `json:"debounce_ms,omitempty"`
+}
+
+// jsonCapability is the wire shape of a Capability.
+type jsonCapability struct {
+	Kind     string `json:"kind"`
+	Value    string `json:"value,omitempty"`
+	ProbeCmd string `json:"probe_cmd,omitempty"`
+}
+
+// jsonReference is the wire shape of a TaskReference.
+type jsonReference struct {
+	Task   string `json:"task"`
+	Output string `json:"output"`
+}
+
+// jsonResource is the wire shape of one entry in the top-level "resources"
+// map: a Directory, CacheVolume, SSHResource, or SecretResource.
+type jsonResource struct {
+	Type  string   `json:"type"`
+	Path  string   `json:"path,omitempty"`
+	Name  string   `json:"name,omitempty"`
+	Globs []string `json:"globs,omitempty"`
+}
+
+// jsonAutoCancel is the wire shape of an AutoCancelPolicy.
+type jsonAutoCancel struct {
+	OnPush        bool   `json:"on_push,omitempty"`
+	OnPullRequest bool   `json:"on_pull_request,omitempty"`
+	Scope         string `json:"scope"`
+	Pending       bool   `json:"pending,omitempty"`
+	Running       bool   `json:"running,omitempty"`
+	PullRequests  bool   `json:"pull_requests,omitempty"`
+}
+
+// jsonConcurrency is the wire shape of a ConcurrencyPolicy.
+type jsonConcurrency struct {
+	Group            string `json:"group"`
+	CancelInProgress bool   `json:"cancel_in_progress,omitempty"`
+	QueueDepth       int    `json:"queue_depth,omitempty"`
+	WaitForPrevious  bool   `json:"wait_for_previous,omitempty"`
+}
+
+// jsonFailurePolicy is the wire shape of a TaskGroup's ContinueOnError/
+// MaxFailures settings.
+type jsonFailurePolicy struct {
+	OnError     string `json:"on_error,omitempty"`
+	MaxFailures int    `json:"max_failures,omitempty"`
 }
 
-// EmitTo writes the pipeline JSON to the given writer.
+// jsonGroup is the wire shape of a Parallel TaskGroup. FailurePolicy is
+// omitted for groups that never called ContinueOnError/MaxFailures.
+type jsonGroup struct {
+	Name          string             `json:"name"`
+	FailurePolicy *jsonFailurePolicy `json:"failure_policy,omitempty"`
+}
+
+// jsonRegistryAuth is the wire shape of a DockerRegistryAuth.
+type jsonRegistryAuth struct {
+	Username       string `json:"username,omitempty"`
+	UsernameSecret string `json:"username_secret,omitempty"`
+	Password       string `json:"password,omitempty"`
+	PasswordSecret string `json:"password_secret,omitempty"`
+	AuthFile       string `json:"auth_file,omitempty"`
+}
+
+// jsonArtifactStore is the wire shape of an ArtifactStore.
+type jsonArtifactStore struct {
+	Type   string `json:"type"`
+	Bucket string `json:"bucket,omitempty"`
+	Path   string `json:"path,omitempty"`
+	Region string `json:"region,omitempty"`
+}
+
+// jsonPipeline is the top-level wire shape EmitTo writes and Parse reads
+// back, in whichever format (JSON, pretty JSON, or YAML) was requested -
+// see emit_format.go. Schema(version) generates a JSON Schema document from
+// this same struct definition via reflection, so the two never drift.
+// ExpandedTasks is populated only when a task declares a matrix: it's
+// ExpandMatrix's output, pre-materialized so a runner doesn't have to
+// reimplement cartesian-product expansion itself.
+type jsonPipeline struct {
+	Version              string                      `json:"version"`
+	Resources            map[string]jsonResource     `json:"resources,omitempty"`
+	Tasks                []jsonTask                  `json:"tasks"`
+	Finally              []jsonTask                  `json:"finally,omitempty"`
+	ExpandedTasks        []jsonTask                  `json:"expanded_tasks,omitempty"`
+	AutoCancel           *jsonAutoCancel             `json:"auto_cancel,omitempty"`
+	Concurrency          *jsonConcurrency            `json:"concurrency,omitempty"`
+	Groups               []jsonGroup                 `json:"groups,omitempty"`
+	ArtifactStore        *jsonArtifactStore          `json:"artifact_store,omitempty"`
+	DockerRegistriesAuth map[string]jsonRegistryAuth `json:"docker_registries_auth,omitempty"`
+}
+
+// EmitTo writes the pipeline to w in the format and schema version
+// configured via EmitFormat/EmitVersion (JSON, uncompressed, by default).
 func (p *Pipeline) EmitTo(w io.Writer) error {
+	doc, err := p.buildDoc()
+	if err != nil {
+		return err
+	}
+	return encodeDoc(w, doc, p.emitFormat)
+}
+
+// buildDoc validates the pipeline and builds the jsonPipeline document that
+// EmitTo encodes. Split out from EmitTo so Schema/Parse's format encoding
+// and decoding logic doesn't need to duplicate this validation.
+func (p *Pipeline) buildDoc() (*jsonPipeline, error) {
 	// Validate
 	taskNames := make(map[string]bool)
 	for _, t := range p.tasks {
 		taskNames[t.name] = true
 	}
+
+	if err := p.resolveReferences(); err != nil {
+		return nil, err
+	}
+
 	for _, t := range p.tasks {
 		if t.command == "" {
 			log.Error().Str("task", t.name).Msg("task has no command")
-			return fmt.Errorf("task %q has no command", t.name)
+			return nil, fmt.Errorf("task %q has no command", t.name)
 		}
 		for _, dep := range t.dependsOn {
 			if !taskNames[dep] {
 				log.Error().Str("task", t.name).Str("dependency", dep).Msg("unknown dependency")
-				return fmt.Errorf("task %q depends on unknown task %q", t.name, dep)
+				return nil, fmt.Errorf("task %q depends on unknown task %q", t.name, dep)
 			}
 		}
 	}
@@ -822,12 +1280,24 @@ func (p *Pipeline) EmitTo(w io.Writer) error {
 	if cycle := p.detectCycle(); cycle != nil {
 		cyclePath := formatCyclePath(cycle)
 		log.Error().Strs("cycle", cycle).Msg("dependency cycle detected")
-		return fmt.Errorf("dependency cycle detected: %s", cyclePath)
+		return nil, fmt.Errorf("dependency cycle detected: %s", cyclePath)
+	}
+
+	if err := p.validateLockedCacheGroups(); err != nil {
+		return nil, err
+	}
+
+	if err := p.validateFinallyTasks(); err != nil {
+		return nil, err
+	}
+
+	if err := p.validateRegistryAuth(); err != nil {
+		return nil, err
 	}
 
-	// Detect version based on usage
+	// Detect version based on usage, unless EmitVersion pinned one.
 	version := "1"
-	hasV2Features := len(p.dirs) > 0 || len(p.caches) > 0
+	hasV2Features := len(p.dirs) > 0 || len(p.caches) > 0 || len(p.sshResources) > 0 || len(p.secretResources) > 0 || p.artifactStore != nil || len(p.registryAuths) > 0
 	for _, t := range p.tasks {
 		if t.container != "" || len(t.mounts) > 0 {
 			hasV2Features = true
@@ -837,55 +1307,8 @@ func (p *Pipeline) EmitTo(w io.Writer) error {
 	if hasV2Features {
 		version = "2"
 	}
-
-	// Build JSON output
-	type jsonMount struct {
-		Resource string `json:"resource"`
-		Path     string `json:"path"`
-		Type     string `json:"type"`
-	}
-
-	type jsonService struct {
-		Image string `json:"image"`
-		Name  string `json:"name"`
-	}
-
-	type jsonTaskInput struct {
-		FromTask   string `json:"from_task"`
-		OutputName string `json:"output"`
-		DestPath   string `json:"dest"`
-	}
-
-	type jsonTask struct {
-		Name       string              `json:"name"`
-		Command    string              `json:"command"`
-		Container  string              `json:"container,omitempty"`
-		Workdir    string              `json:"workdir,omitempty"`
-		Env        map[string]string   `json:"env,omitempty"`
-		Mounts     []jsonMount         `json:"mounts,omitempty"`
-		Inputs     []string            `json:"inputs,omitempty"`       // v1-style file patterns
-		TaskInputs []jsonTaskInput     `json:"task_inputs,omitempty"`  // v2-style inputs from other tasks
-		Outputs    map[string]string   `json:"outputs,omitempty"`
-		DependsOn  []string            `json:"depends_on,omitempty"`
-		When       string              `json:"when,omitempty"`
-		Secrets    []string            `json:"secrets,omitempty"`
-		Matrix     map[string][]string `json:"matrix,omitempty"`
-		Services   []jsonService       `json:"services,omitempty"`
-		Retry      int                 `json:"retry,omitempty"`
-		Timeout    int                 `json:"timeout,omitempty"`
-	}
-
-	type jsonResource struct {
-		Type  string   `json:"type"`
-		Path  string   `json:"path,omitempty"`
-		Name  string   `json:"name,omitempty"`
-		Globs []string `json:"globs,omitempty"`
-	}
-
-	type jsonPipeline struct {
-		Version   string                  `json:"version"`
-		Resources map[string]jsonResource `json:"resources,omitempty"`
-		Tasks     []jsonTask              `json:"tasks"`
+	if p.schemaVersion != "" {
+		version = p.schemaVersion
 	}
 
 	// Build resources map
@@ -905,11 +1328,30 @@ func (p *Pipeline) EmitTo(w io.Writer) error {
 				Name: c.name,
 			}
 		}
+		for _, s := range p.sshResources {
+			resources[s.ID()] = jsonResource{
+				Type: "ssh",
+				Name: s.name,
+				Path: s.keyPath,
+			}
+		}
+		for _, s := range p.secretResources {
+			resources[s.ID()] = jsonResource{
+				Type: "secret",
+				Name: s.name,
+				Path: secretSourceRef(s),
+			}
+		}
 	}
 
-	// Build tasks
-	tasks := make([]jsonTask, len(p.tasks))
-	for i, t := range p.tasks {
+	tasksByName := make(map[string]*Task, len(p.tasks))
+	for _, t := range p.tasks {
+		tasksByName[t.name] = t
+	}
+
+	// toJSONTask converts a single *Task into its jsonTask representation.
+	// Shared by the main task list and the finally task list below.
+	toJSONTask := func(t *Task) jsonTask {
 		var mounts []jsonMount
 		if len(t.mounts) > 0 {
 			mounts = make([]jsonMount, len(t.mounts))
@@ -918,6 +1360,15 @@ func (p *Pipeline) EmitTo(w io.Writer) error {
 					Resource: m.resource,
 					Path:     m.path,
 					Type:     m.mountType,
+					Sharing:  string(m.sharing),
+					Mode:     m.mode,
+					ReadOnly: m.readOnly,
+					Required: m.secretRequired,
+				}
+				if m.hasOwner {
+					uid, gid := m.uid, m.gid
+					mounts[j].UID = &uid
+					mounts[j].GID = &gid
 				}
 			}
 		}
@@ -928,8 +1379,15 @@ func (p *Pipeline) EmitTo(w io.Writer) error {
 		}
 
 		var outputs map[string]string
+		var outputKeys map[string]string
 		if len(t.outputs) > 0 {
 			outputs = t.outputs
+			if p.artifactStore != nil {
+				outputKeys = make(map[string]string, len(t.outputs))
+				for name := range t.outputs {
+					outputKeys[name] = contentKey(t.name, name, t.inputs)
+				}
+			}
 		}
 
 		// Convert taskInputs to JSON
@@ -942,25 +1400,67 @@ func (p *Pipeline) EmitTo(w io.Writer) error {
 					OutputName: ti.outputName,
 					DestPath:   ti.destPath,
 				}
+				if p.artifactStore != nil {
+					if src, ok := tasksByName[ti.fromTask]; ok {
+						taskInputs[j].Key = contentKey(src.name, ti.outputName, src.inputs)
+					}
+				}
 			}
 		}
 
-		tasks[i] = jsonTask{
+		return jsonTask{
 			Name:       t.name,
 			Command:    t.command,
 			Container:  t.container,
 			Workdir:    t.workdir,
 			Env:        env,
 			Mounts:     mounts,
-			Inputs:     t.inputs,     // v1-style file patterns
-			TaskInputs: taskInputs,   // v2-style inputs from other tasks
+			Inputs:     t.inputs,   // v1-style file patterns
+			TaskInputs: taskInputs, // v2-style inputs from other tasks
 			Outputs:    outputs,
+			OutputKeys: outputKeys,
 			DependsOn:  t.dependsOn,
 			When:       t.when,
-			Secrets:    t.secrets,
-			Matrix:     t.matrix,
-			Retry:      t.retry,
-			Timeout:    t.timeout,
+			WhenAST: func() interface{} {
+				if t.whenAST == nil {
+					return nil
+				}
+				return t.whenAST.toJSON()
+			}(),
+			Secrets: t.secrets,
+			Matrix: func() map[string]interface{} {
+				if len(t.matrix) == 0 && len(t.matrixExclude) == 0 && len(t.matrixInclude) == 0 {
+					return nil
+				}
+				m := make(map[string]interface{}, len(t.matrix)+2)
+				for k, v := range t.matrix {
+					m[k] = v
+				}
+				if len(t.matrixExclude) > 0 {
+					m["exclude"] = t.matrixExclude
+				}
+				if len(t.matrixInclude) > 0 {
+					m["include"] = t.matrixInclude
+				}
+				if t.matrixExpand {
+					m["combinations"] = expandMatrixCombinations(t)
+				}
+				return m
+			}(),
+			Retry:         t.retry,
+			Timeout:       t.timeout,
+			NonCancelable: t.nonCancelable,
+			RunsOn:        t.runsOn,
+			References: func() []jsonReference {
+				if len(t.references) == 0 {
+					return nil
+				}
+				refs := make([]jsonReference, len(t.references))
+				for j, r := range t.references {
+					refs[j] = jsonReference{Task: r.task, Output: r.output}
+				}
+				return refs
+			}(),
 			Services: func() []jsonService {
 				if len(t.services) == 0 {
 					return nil
@@ -971,14 +1471,115 @@ func (p *Pipeline) EmitTo(w io.Writer) error {
 				}
 				return svcs
 			}(),
+			Target: t.targetName,
+			Group:  t.groupName,
+			Watch: func() *jsonWatch {
+				if len(t.watchSrc) == 0 {
+					return nil
+				}
+				return &jsonWatch{Src: t.watchSrc, DebounceMs: t.watchDebounce}
+			}(),
+			Requires: func() []jsonCapability {
+				if len(t.requires) == 0 {
+					return nil
+				}
+				caps := make([]jsonCapability, len(t.requires))
+				for j, c := range t.requires {
+					caps[j] = jsonCapability{Kind: c.Kind, Value: c.Value, ProbeCmd: c.ProbeCmd}
+				}
+				return caps
+			}(),
+			Skip:    t.skipReason,
+			NoCache: t.noCacheReason,
+		}
+	}
+
+	tasks := make([]jsonTask, len(p.tasks))
+	cacheKeys := make(map[string]string, len(p.tasks))
+	for i, t := range p.tasks {
+		tasks[i] = toJSONTask(t)
+		if key, err := p.cacheKeyFor(t, cacheKeys); err == nil {
+			tasks[i].CacheKey = key
+		} else {
+			log.Debug().Str("task", t.name).Err(err).Msg("skipping cache_key: input files could not be hashed")
+		}
+	}
+
+	var finallyTasks []jsonTask
+	if len(p.finallyTasks) > 0 {
+		finallyTasks = make([]jsonTask, len(p.finallyTasks))
+		for i, t := range p.finallyTasks {
+			finallyTasks[i] = toJSONTask(t)
+		}
+	}
+
+	var expandedTasks []jsonTask
+	if hasMatrixTasks(p) {
+		expanded, err := ExpandMatrix(p)
+		if err != nil {
+			return nil, fmt.Errorf("building expanded_tasks: %w", err)
+		}
+		expandedTasks = make([]jsonTask, len(expanded.tasks))
+		for i, t := range expanded.tasks {
+			expandedTasks[i] = toJSONTask(t)
 		}
 	}
 
 	out := jsonPipeline{
-		Version:   version,
-		Resources: resources,
-		Tasks:     tasks,
+		Version:       version,
+		Resources:     resources,
+		Tasks:         tasks,
+		Finally:       finallyTasks,
+		ExpandedTasks: expandedTasks,
+	}
+	if p.autoCancel != nil {
+		out.AutoCancel = &jsonAutoCancel{
+			OnPush:        p.autoCancel.OnPush,
+			OnPullRequest: p.autoCancel.OnPullRequest,
+			Scope:         string(p.autoCancel.Scope),
+			Pending:       p.autoCancel.Pending,
+			Running:       p.autoCancel.Running,
+			PullRequests:  p.autoCancel.PullRequests,
+		}
+	}
+	if p.concurrency != nil {
+		out.Concurrency = &jsonConcurrency{
+			Group:            p.concurrency.Group,
+			CancelInProgress: p.concurrency.CancelInProgress,
+			QueueDepth:       p.concurrency.QueueDepth,
+			WaitForPrevious:  p.concurrency.WaitForPrevious,
+		}
+	}
+	if p.artifactStore != nil {
+		out.ArtifactStore = &jsonArtifactStore{
+			Type:   p.artifactStore.kind,
+			Bucket: p.artifactStore.bucket,
+			Path:   p.artifactStore.path,
+			Region: p.artifactStore.region,
+		}
+	}
+	if len(p.registryAuths) > 0 {
+		out.DockerRegistriesAuth = make(map[string]jsonRegistryAuth, len(p.registryAuths))
+		for _, r := range p.registryAuths {
+			out.DockerRegistriesAuth[r.host] = jsonRegistryAuth{
+				Username:       r.username,
+				UsernameSecret: r.usernameSecret,
+				Password:       r.password,
+				PasswordSecret: r.passwordSecret,
+				AuthFile:       r.authFilePath,
+			}
+		}
+	}
+	if len(p.groups) > 0 {
+		out.Groups = make([]jsonGroup, len(p.groups))
+		for i, g := range p.groups {
+			jg := jsonGroup{Name: g.name}
+			if g.onError != "" || g.maxFailures > 0 {
+				jg.FailurePolicy = &jsonFailurePolicy{OnError: g.onError, MaxFailures: g.maxFailures}
+			}
+			out.Groups[i] = jg
+		}
 	}
 
-	return json.NewEncoder(w).Encode(out)
+	return &out, nil
 }
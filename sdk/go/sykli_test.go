@@ -679,7 +679,7 @@ func TestCycleErrorShowsPath(t *testing.T) {
 
 	errStr := err.Error()
 	// Error should mention both tasks in the cycle
-	if !contains(errStr, "a") || !contains(errStr, "b") {
+	if !containsStr(errStr, "a") || !containsStr(errStr, "b") {
 		t.Errorf("cycle error should mention tasks in cycle, got: %v", errStr)
 	}
 }
@@ -734,11 +734,11 @@ func containsCycleError(err error) bool {
 		return false
 	}
 	s := err.Error()
-	return contains(s, "cycle") || contains(s, "circular")
+	return containsStr(s, "cycle") || containsStr(s, "circular")
 }
 
 // Simple string contains helper
-func contains(s, substr string) bool {
+func containsStr(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsAt(s, substr))
 }
 
@@ -220,6 +220,17 @@ type Result struct {
 	Output   string // Captured stdout/stderr
 	Duration int64  // Execution time in milliseconds
 	Error    error  // Non-nil if execution failed
+
+	// Criticality lets a Target mark a failure as fatal to the whole run.
+	// "high" is the only value an Executor acts on today: the first
+	// Result with Success == false and Criticality == "high" triggers
+	// cooperative shutdown of every task still waiting to start. Leave
+	// it empty for a failure that should only block its own dependents.
+	Criticality string
+
+	// Cached is true when this Result came from an Executor's registered
+	// actioncache.Cache instead of a live Target.RunTask call.
+	Cached bool
 }
 
 // VolumeOptions for creating volumes.
@@ -303,6 +314,13 @@ type K8sTaskOptions struct {
 
 	// Namespace overrides the default namespace.
 	Namespace string
+
+	// --- Downward API ---
+
+	// DownwardEnv projects pod-runtime metadata (podIP, node name, a
+	// label, a resource limit, ...) into environment variables. See
+	// ValidateK8sOptions for the set of supported FieldPath values.
+	DownwardEnv []K8sDownwardEnv
 }
 
 // K8sResources specifies compute resources.
@@ -362,6 +380,7 @@ type K8sVolume struct {
 	EmptyDir  *K8sEmptyDirVolume
 	HostPath  *K8sHostPathVolume
 	PVC       *K8sPVCVolume
+	Downward  *K8sDownwardVolume
 }
 
 type K8sConfigMapVolume struct{ Name string }
@@ -370,6 +389,45 @@ type K8sEmptyDirVolume struct{ Medium, SizeLimit string }
 type K8sHostPathVolume struct{ Path, Type string }
 type K8sPVCVolume struct{ ClaimName string }
 
+// K8sDownwardVolume projects pod-runtime metadata into files under a
+// volume mount, one file per Item.
+type K8sDownwardVolume struct {
+	Items []K8sDownwardVolumeItem
+}
+
+// K8sDownwardVolumeItem projects a single downward API field to Path,
+// relative to the volume's mount path.
+type K8sDownwardVolumeItem struct {
+	Path             string
+	FieldPath        string
+	ResourceFieldRef *K8sResourceFieldRef
+}
+
+// K8sDownwardEnv projects a single piece of pod-runtime metadata into an
+// environment variable, from either the pod's own spec/metadata/status
+// (FieldPath) or a container's compute resources (ResourceFieldRef).
+// Exactly one of FieldPath or ResourceFieldRef must be set.
+type K8sDownwardEnv struct {
+	Name string
+
+	// FieldPath selects from the pod's own metadata/spec/status, e.g.
+	// "metadata.name" or "status.podIP". See ValidateK8sOptions for the
+	// supported set.
+	FieldPath string
+
+	// ResourceFieldRef selects a container's resource request/limit
+	// instead of a field on the pod.
+	ResourceFieldRef *K8sResourceFieldRef
+}
+
+// K8sResourceFieldRef selects a compute resource quantity to project, via
+// K8sDownwardEnv.ResourceFieldRef or K8sDownwardVolumeItem.ResourceFieldRef.
+type K8sResourceFieldRef struct {
+	ContainerName string // defaults to the task's own container
+	Resource      string // e.g. "limits.cpu", "requests.memory"
+	Divisor       string // e.g. "1m", "1Mi"; empty means the Kubernetes default
+}
+
 // =============================================================================
 // TASK K8S EXTENSION
 // =============================================================================
@@ -447,6 +505,11 @@ func MergeK8sOptions(defaults, task *K8sTaskOptions) *K8sTaskOptions {
 		result.Volumes = task.Volumes
 	}
 
+	// DownwardEnv merges by Name instead of replacing wholesale, since
+	// tasks commonly want to inherit cluster defaults and only override
+	// a couple of entries.
+	result.DownwardEnv = mergeDownwardEnv(defaults.DownwardEnv, task.DownwardEnv)
+
 	// Structs: task replaces if non-nil
 	if task.Affinity != nil {
 		result.Affinity = task.Affinity
@@ -458,6 +521,37 @@ func MergeK8sOptions(defaults, task *K8sTaskOptions) *K8sTaskOptions {
 	return &result
 }
 
+// mergeDownwardEnv merges two K8sDownwardEnv slices by Name, with task's
+// entries overriding defaults' and defaults' order preserved for entries
+// task doesn't touch.
+func mergeDownwardEnv(defaults, task []K8sDownwardEnv) []K8sDownwardEnv {
+	if len(defaults) == 0 {
+		return task
+	}
+	if len(task) == 0 {
+		return defaults
+	}
+
+	byName := make(map[string]K8sDownwardEnv, len(defaults)+len(task))
+	order := make([]string, 0, len(defaults)+len(task))
+	for _, e := range defaults {
+		byName[e.Name] = e
+		order = append(order, e.Name)
+	}
+	for _, e := range task {
+		if _, ok := byName[e.Name]; !ok {
+			order = append(order, e.Name)
+		}
+		byName[e.Name] = e
+	}
+
+	merged := make([]K8sDownwardEnv, len(order))
+	for i, name := range order {
+		merged[i] = byName[name]
+	}
+	return merged
+}
+
 // mergeMaps merges two string maps, with b's values overriding a's.
 func mergeMaps(a, b map[string]string) map[string]string {
 	if len(a) == 0 && len(b) == 0 {
@@ -558,6 +652,105 @@ func ValidateK8sOptions(opts *K8sTaskOptions) []error {
 				Message: "mount path must be absolute (start with /)",
 			})
 		}
+		if v.Downward != nil {
+			for j, item := range v.Downward.Items {
+				errs = append(errs, validateK8sDownwardVolumeItem(fmt.Sprintf("volumes[%d].downward.items[%d]", i, j), item)...)
+			}
+		}
+	}
+
+	// Validate downward API env vars
+	for i, e := range opts.DownwardEnv {
+		errs = append(errs, validateK8sDownwardEnv(fmt.Sprintf("downwardEnv[%d]", i), e)...)
+	}
+
+	return errs
+}
+
+// k8sDownwardFieldPaths are the fieldPath values ValidateK8sOptions
+// accepts outright. metadata.labels and metadata.annotations are also
+// accepted with a labels['key']/annotations['key'] subscript, checked
+// separately by k8sDownwardSubscriptPattern.
+var k8sDownwardFieldPaths = []string{
+	"metadata.name", "metadata.namespace", "metadata.uid",
+	"metadata.labels", "metadata.annotations",
+	"spec.nodeName", "spec.serviceAccountName",
+	"status.hostIP", "status.podIP", "status.podIPs",
+}
+
+var k8sDownwardSubscriptPattern = regexp.MustCompile(`^(metadata\.labels|metadata\.annotations)\['([^']+)'\]$`)
+
+func validateK8sFieldPath(field, value string) error {
+	if value == "" {
+		return K8sValidationError{Field: field, Message: "fieldPath is required"}
+	}
+	if contains(k8sDownwardFieldPaths, value) {
+		return nil
+	}
+	if k8sDownwardSubscriptPattern.MatchString(value) {
+		return nil
+	}
+	if strings.HasPrefix(value, "metadata.labels[") || strings.HasPrefix(value, "metadata.annotations[") {
+		return K8sValidationError{
+			Field:   field,
+			Value:   value,
+			Message: "subscript must be a quoted, non-empty key, e.g. metadata.labels['app']",
+		}
+	}
+	return K8sValidationError{
+		Field:   field,
+		Value:   value,
+		Message: fmt.Sprintf("unsupported fieldPath, must be one of %s, or metadata.labels['key']/metadata.annotations['key']", strings.Join(k8sDownwardFieldPaths, ", ")),
+	}
+}
+
+func validateK8sDownwardEnv(field string, e K8sDownwardEnv) []error {
+	var errs []error
+	if e.Name == "" {
+		errs = append(errs, K8sValidationError{Field: field + ".name", Message: "name is required"})
+	}
+
+	hasFieldPath := e.FieldPath != ""
+	hasResourceRef := e.ResourceFieldRef != nil
+	switch {
+	case hasFieldPath == hasResourceRef:
+		errs = append(errs, K8sValidationError{
+			Field:   field,
+			Value:   e.FieldPath,
+			Message: "exactly one of fieldPath or resourceFieldRef is required",
+		})
+	case hasFieldPath:
+		if err := validateK8sFieldPath(field+".fieldPath", e.FieldPath); err != nil {
+			errs = append(errs, err)
+		}
+	case hasResourceRef && e.ResourceFieldRef.Resource == "":
+		errs = append(errs, K8sValidationError{Field: field + ".resourceFieldRef.resource", Message: "resource is required"})
+	}
+
+	return errs
+}
+
+func validateK8sDownwardVolumeItem(field string, item K8sDownwardVolumeItem) []error {
+	var errs []error
+	if item.Path == "" {
+		errs = append(errs, K8sValidationError{Field: field + ".path", Message: "path is required"})
+	}
+
+	hasFieldPath := item.FieldPath != ""
+	hasResourceRef := item.ResourceFieldRef != nil
+	switch {
+	case hasFieldPath == hasResourceRef:
+		errs = append(errs, K8sValidationError{
+			Field:   field,
+			Value:   item.FieldPath,
+			Message: "exactly one of fieldPath or resourceFieldRef is required",
+		})
+	case hasFieldPath:
+		if err := validateK8sFieldPath(field+".fieldPath", item.FieldPath); err != nil {
+			errs = append(errs, err)
+		}
+	case hasResourceRef && item.ResourceFieldRef.Resource == "":
+		errs = append(errs, K8sValidationError{Field: field + ".resourceFieldRef.resource", Message: "resource is required"})
 	}
 
 	return errs
@@ -0,0 +1,358 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sykli "sykli.dev/go"
+)
+
+// mainContainerName is the name of the container running task.Command;
+// service sidecars are named after their ServiceSpec.
+const mainContainerName = "task"
+
+// buildPod renders task into a Pod spec. podTemplate, if non-nil, seeds
+// the PodSpec before task-specific fields (container, env, node
+// selector, tolerations, ...) are layered on top - a field the template
+// sets and task.K8s leaves zero is kept, everything task.K8s sets wins.
+// defaultImage is used when task.Image is empty.
+func buildPod(name string, task sykli.TaskSpec, defaultImage string, podTemplate *corev1.PodSpec) (*corev1.Pod, error) {
+	spec := corev1.PodSpec{RestartPolicy: corev1.RestartPolicyNever}
+	if podTemplate != nil {
+		spec = *podTemplate.DeepCopy()
+		spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+
+	image := task.Image
+	if image == "" {
+		image = defaultImage
+	}
+
+	container := corev1.Container{
+		Name:       mainContainerName,
+		Image:      image,
+		Command:    []string{"/bin/sh", "-c", task.Command},
+		WorkingDir: task.Workdir,
+	}
+
+	meta := metav1.ObjectMeta{GenerateName: name + "-"}
+
+	opts := task.K8s
+	if opts != nil {
+		if len(opts.Labels) > 0 {
+			meta.Labels = opts.Labels
+		}
+		if len(opts.Annotations) > 0 {
+			meta.Annotations = opts.Annotations
+		}
+		if len(opts.NodeSelector) > 0 {
+			spec.NodeSelector = opts.NodeSelector
+		}
+		if opts.PriorityClassName != "" {
+			spec.PriorityClassName = opts.PriorityClassName
+		}
+		if opts.ServiceAccount != "" {
+			spec.ServiceAccountName = opts.ServiceAccount
+		}
+		if opts.HostNetwork {
+			spec.HostNetwork = opts.HostNetwork
+		}
+		if opts.DNSPolicy != "" {
+			spec.DNSPolicy = corev1.DNSPolicy(opts.DNSPolicy)
+		}
+
+		for _, tol := range opts.Tolerations {
+			spec.Tolerations = append(spec.Tolerations, corev1.Toleration{
+				Key:      tol.Key,
+				Operator: corev1.TolerationOperator(tol.Operator),
+				Value:    tol.Value,
+				Effect:   corev1.TaintEffect(tol.Effect),
+			})
+		}
+
+		if opts.Affinity != nil {
+			spec.Affinity = buildAffinity(opts.Affinity)
+		}
+		if opts.SecurityContext != nil {
+			container.SecurityContext = buildSecurityContext(opts.SecurityContext)
+		}
+
+		resources, err := buildResources(opts.Resources, opts.GPU)
+		if err != nil {
+			return nil, fmt.Errorf("k8s: task %q: %w", task.Name, err)
+		}
+		container.Resources = resources
+
+		volumes, mounts, err := buildK8sVolumes(opts.Volumes)
+		if err != nil {
+			return nil, fmt.Errorf("k8s: task %q: %w", task.Name, err)
+		}
+		spec.Volumes = append(spec.Volumes, volumes...)
+		container.VolumeMounts = append(container.VolumeMounts, mounts...)
+
+		env, err := buildDownwardEnv(opts.DownwardEnv, mainContainerName)
+		if err != nil {
+			return nil, fmt.Errorf("k8s: task %q: %w", task.Name, err)
+		}
+		container.Env = append(container.Env, env...)
+	}
+
+	for _, key := range sortedKeys(task.Env) {
+		container.Env = append(container.Env, corev1.EnvVar{Name: key, Value: task.Env[key]})
+	}
+
+	for _, m := range task.Mounts {
+		if m.Volume == nil {
+			continue
+		}
+		volName := "mount-" + m.Volume.ID()
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name: volName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: m.Volume.Reference()},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: volName, MountPath: m.Path})
+	}
+
+	containers := []corev1.Container{container}
+	for _, svc := range task.Services {
+		containers = append(containers, corev1.Container{Name: svc.Name, Image: svc.Image})
+	}
+	spec.Containers = containers
+
+	return &corev1.Pod{ObjectMeta: meta, Spec: spec}, nil
+}
+
+// buildResources turns K8sResources and a GPU count into a
+// corev1.ResourceRequirements, with CPU/Memory as shorthand for both
+// request and limit, RequestX/LimitX overriding them individually.
+func buildResources(r sykli.K8sResources, gpu int) (corev1.ResourceRequirements, error) {
+	requests := corev1.ResourceList{}
+	limits := corev1.ResourceList{}
+
+	set := func(list corev1.ResourceList, name corev1.ResourceName, value string) error {
+		if value == "" {
+			return nil
+		}
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return fmt.Errorf("parsing %s %q: %w", name, value, err)
+		}
+		list[name] = q
+		return nil
+	}
+
+	for _, err := range []error{
+		set(requests, corev1.ResourceCPU, r.CPU),
+		set(limits, corev1.ResourceCPU, r.CPU),
+		set(requests, corev1.ResourceMemory, r.Memory),
+		set(limits, corev1.ResourceMemory, r.Memory),
+		set(requests, corev1.ResourceCPU, r.RequestCPU),
+		set(limits, corev1.ResourceCPU, r.LimitCPU),
+		set(requests, corev1.ResourceMemory, r.RequestMemory),
+		set(limits, corev1.ResourceMemory, r.LimitMemory),
+	} {
+		if err != nil {
+			return corev1.ResourceRequirements{}, err
+		}
+	}
+
+	if gpu > 0 {
+		q := resource.MustParse(strconv.Itoa(gpu))
+		requests[nvidiaGPUResource] = q
+		limits[nvidiaGPUResource] = q
+	}
+
+	result := corev1.ResourceRequirements{}
+	if len(requests) > 0 {
+		result.Requests = requests
+	}
+	if len(limits) > 0 {
+		result.Limits = limits
+	}
+	return result, nil
+}
+
+// nvidiaGPUResource is the device plugin resource name the NVIDIA GPU
+// device plugin registers on GPU nodes; there is no in-tree alternative.
+const nvidiaGPUResource corev1.ResourceName = "nvidia.com/gpu"
+
+func buildAffinity(a *sykli.K8sAffinity) *corev1.Affinity {
+	affinity := &corev1.Affinity{}
+	if a.NodeAffinity != nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+		if len(a.NodeAffinity.RequiredLabels) > 0 {
+			affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: matchExpressions(a.NodeAffinity.RequiredLabels)}},
+			}
+		}
+		for _, expr := range matchExpressions(a.NodeAffinity.PreferredLabels) {
+			affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+				affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+				corev1.PreferredSchedulingTerm{Weight: 1, Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{expr}}},
+			)
+		}
+	}
+	if a.PodAffinity != nil {
+		affinity.PodAffinity = &corev1.PodAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{LabelSelector: &metav1.LabelSelector{MatchLabels: a.PodAffinity.RequiredLabels}, TopologyKey: a.PodAffinity.TopologyKey},
+			},
+		}
+	}
+	if a.PodAntiAffinity != nil {
+		affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{LabelSelector: &metav1.LabelSelector{MatchLabels: a.PodAntiAffinity.RequiredLabels}, TopologyKey: a.PodAntiAffinity.TopologyKey},
+			},
+		}
+	}
+	return affinity
+}
+
+func matchExpressions(labels map[string]string) []corev1.NodeSelectorRequirement {
+	exprs := make([]corev1.NodeSelectorRequirement, 0, len(labels))
+	for _, key := range sortedKeys(labels) {
+		exprs = append(exprs, corev1.NodeSelectorRequirement{Key: key, Operator: corev1.NodeSelectorOpIn, Values: []string{labels[key]}})
+	}
+	return exprs
+}
+
+func buildSecurityContext(sc *sykli.K8sSecurityContext) *corev1.SecurityContext {
+	out := &corev1.SecurityContext{
+		RunAsUser:    sc.RunAsUser,
+		RunAsGroup:   sc.RunAsGroup,
+		RunAsNonRoot: &sc.RunAsNonRoot,
+		Privileged:   &sc.Privileged,
+	}
+	if sc.ReadOnlyRootFilesystem {
+		out.ReadOnlyRootFilesystem = &sc.ReadOnlyRootFilesystem
+	}
+	if len(sc.AddCapabilities) > 0 || len(sc.DropCapabilities) > 0 {
+		out.Capabilities = &corev1.Capabilities{}
+		for _, c := range sc.AddCapabilities {
+			out.Capabilities.Add = append(out.Capabilities.Add, corev1.Capability(c))
+		}
+		for _, c := range sc.DropCapabilities {
+			out.Capabilities.Drop = append(out.Capabilities.Drop, corev1.Capability(c))
+		}
+	}
+	return out
+}
+
+// buildK8sVolumes translates K8sVolume entries (ConfigMap/Secret/
+// EmptyDir/HostPath/PVC/Downward) into Pod volumes plus the main
+// container's mounts for them.
+func buildK8sVolumes(vols []sykli.K8sVolume) ([]corev1.Volume, []corev1.VolumeMount, error) {
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+
+	for _, v := range vols {
+		vol := corev1.Volume{Name: v.Name}
+		switch {
+		case v.ConfigMap != nil:
+			vol.VolumeSource = corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: v.ConfigMap.Name},
+			}}
+		case v.Secret != nil:
+			vol.VolumeSource = corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: v.Secret.Name}}
+		case v.EmptyDir != nil:
+			src := &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMedium(v.EmptyDir.Medium)}
+			if v.EmptyDir.SizeLimit != "" {
+				q, err := resource.ParseQuantity(v.EmptyDir.SizeLimit)
+				if err != nil {
+					return nil, nil, fmt.Errorf("volume %q: parsing sizeLimit %q: %w", v.Name, v.EmptyDir.SizeLimit, err)
+				}
+				src.SizeLimit = &q
+			}
+			vol.VolumeSource = corev1.VolumeSource{EmptyDir: src}
+		case v.HostPath != nil:
+			var t *corev1.HostPathType
+			if v.HostPath.Type != "" {
+				pt := corev1.HostPathType(v.HostPath.Type)
+				t = &pt
+			}
+			vol.VolumeSource = corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: v.HostPath.Path, Type: t}}
+		case v.PVC != nil:
+			vol.VolumeSource = corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: v.PVC.ClaimName}}
+		case v.Downward != nil:
+			items := make([]corev1.DownwardAPIVolumeFile, 0, len(v.Downward.Items))
+			for _, item := range v.Downward.Items {
+				file := corev1.DownwardAPIVolumeFile{Path: item.Path}
+				if item.FieldPath != "" {
+					file.FieldRef = &corev1.ObjectFieldSelector{FieldPath: item.FieldPath}
+				}
+				if item.ResourceFieldRef != nil {
+					ref, err := resourceFieldRef(item.ResourceFieldRef, mainContainerName)
+					if err != nil {
+						return nil, nil, fmt.Errorf("volume %q: %w", v.Name, err)
+					}
+					file.ResourceFieldRef = ref
+				}
+				items = append(items, file)
+			}
+			vol.VolumeSource = corev1.VolumeSource{DownwardAPI: &corev1.DownwardAPIVolumeSource{Items: items}}
+		default:
+			continue
+		}
+		volumes = append(volumes, vol)
+		mounts = append(mounts, corev1.VolumeMount{Name: v.Name, MountPath: v.MountPath})
+	}
+	return volumes, mounts, nil
+}
+
+// buildDownwardEnv turns K8sDownwardEnv entries into EnvVars sourced from
+// the pod's own fields or a container's resource requests/limits.
+func buildDownwardEnv(entries []sykli.K8sDownwardEnv, containerName string) ([]corev1.EnvVar, error) {
+	env := make([]corev1.EnvVar, 0, len(entries))
+	for _, e := range entries {
+		v := corev1.EnvVar{Name: e.Name}
+		switch {
+		case e.FieldPath != "":
+			v.ValueFrom = &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: e.FieldPath}}
+		case e.ResourceFieldRef != nil:
+			ref, err := resourceFieldRef(e.ResourceFieldRef, containerName)
+			if err != nil {
+				return nil, fmt.Errorf("downward env %q: %w", e.Name, err)
+			}
+			v.ValueFrom = &corev1.EnvVarSource{ResourceFieldRef: ref}
+		default:
+			return nil, fmt.Errorf("downward env %q: neither FieldPath nor ResourceFieldRef is set", e.Name)
+		}
+		env = append(env, v)
+	}
+	return env, nil
+}
+
+func resourceFieldRef(ref *sykli.K8sResourceFieldRef, defaultContainer string) (*corev1.ResourceFieldSelector, error) {
+	out := &corev1.ResourceFieldSelector{
+		ContainerName: ref.ContainerName,
+		Resource:      ref.Resource,
+	}
+	if out.ContainerName == "" {
+		out.ContainerName = defaultContainer
+	}
+	if ref.Divisor != "" {
+		q, err := resource.ParseQuantity(ref.Divisor)
+		if err != nil {
+			return nil, fmt.Errorf("parsing divisor %q: %w", ref.Divisor, err)
+		}
+		out.Divisor = q
+	}
+	return out, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,134 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	sykli "sykli.dev/go"
+)
+
+func TestBuildPodSetsCommandAndImage(t *testing.T) {
+	pod, err := buildPod("build", sykli.TaskSpec{Name: "build", Command: "go build ./...", Image: "golang:1.21"}, "alpine", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pod.Spec.Containers) != 1 {
+		t.Fatalf("expected 1 container, got %d", len(pod.Spec.Containers))
+	}
+	c := pod.Spec.Containers[0]
+	if c.Image != "golang:1.21" {
+		t.Errorf("expected image golang:1.21, got %q", c.Image)
+	}
+	if len(c.Command) != 3 || c.Command[2] != "go build ./..." {
+		t.Errorf("expected command to run the task's shell command, got %v", c.Command)
+	}
+}
+
+func TestBuildPodFallsBackToDefaultImage(t *testing.T) {
+	pod, err := buildPod("t", sykli.TaskSpec{Name: "t", Command: "echo hi"}, "alpine", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pod.Spec.Containers[0].Image != "alpine" {
+		t.Errorf("expected default image alpine, got %q", pod.Spec.Containers[0].Image)
+	}
+}
+
+func TestBuildPodSetsResourcesAndGPU(t *testing.T) {
+	pod, err := buildPod("t", sykli.TaskSpec{
+		Name: "t", Command: "echo hi",
+		K8s: &sykli.K8sTaskOptions{Resources: sykli.K8sResources{CPU: "2", Memory: "4Gi"}, GPU: 1},
+	}, "alpine", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := pod.Spec.Containers[0].Resources
+	if res.Requests.Cpu().String() != "2" || res.Limits.Cpu().String() != "2" {
+		t.Errorf("expected both request and limit CPU set from shorthand, got %+v", res)
+	}
+	if q, ok := res.Requests[nvidiaGPUResource]; !ok || q.String() != "1" {
+		t.Errorf("expected a GPU request, got %+v", res.Requests)
+	}
+}
+
+func TestBuildPodSetsNodeSelectorAndTolerations(t *testing.T) {
+	pod, err := buildPod("t", sykli.TaskSpec{
+		Name: "t", Command: "echo hi",
+		K8s: &sykli.K8sTaskOptions{
+			NodeSelector: map[string]string{"pool": "gpu"},
+			Tolerations:  []sykli.K8sToleration{{Key: "gpu", Operator: "Exists", Effect: "NoSchedule"}},
+		},
+	}, "alpine", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pod.Spec.NodeSelector["pool"] != "gpu" {
+		t.Errorf("expected node selector to carry through, got %+v", pod.Spec.NodeSelector)
+	}
+	if len(pod.Spec.Tolerations) != 1 || pod.Spec.Tolerations[0].Key != "gpu" {
+		t.Errorf("expected a toleration for gpu, got %+v", pod.Spec.Tolerations)
+	}
+}
+
+func TestBuildPodAppliesPodTemplateAsBase(t *testing.T) {
+	template := &corev1.PodSpec{ServiceAccountName: "default-sa", NodeSelector: map[string]string{"pool": "general"}}
+	pod, err := buildPod("t", sykli.TaskSpec{
+		Name: "t", Command: "echo hi",
+		K8s: &sykli.K8sTaskOptions{NodeSelector: map[string]string{"pool": "gpu"}},
+	}, "alpine", template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pod.Spec.ServiceAccountName != "default-sa" {
+		t.Errorf("expected the template's service account to carry through untouched, got %q", pod.Spec.ServiceAccountName)
+	}
+	if pod.Spec.NodeSelector["pool"] != "gpu" {
+		t.Errorf("expected the task's K8s options to override the template's node selector, got %+v", pod.Spec.NodeSelector)
+	}
+}
+
+func TestBuildPodAddsServiceSidecars(t *testing.T) {
+	pod, err := buildPod("t", sykli.TaskSpec{
+		Name: "t", Command: "echo hi",
+		Services: []sykli.ServiceSpec{{Name: "postgres", Image: "postgres:16"}},
+	}, "alpine", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pod.Spec.Containers) != 2 || pod.Spec.Containers[1].Name != "postgres" {
+		t.Errorf("expected a postgres sidecar container, got %+v", pod.Spec.Containers)
+	}
+}
+
+func TestBuildPodMountsPVCFromTaskVolume(t *testing.T) {
+	pod, err := buildPod("t", sykli.TaskSpec{
+		Name: "t", Command: "echo hi",
+		Mounts: []sykli.MountSpec{{Volume: fakeVolume{id: "src", ref: "src-pvc"}, Path: "/src"}},
+	}, "alpine", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pod.Spec.Volumes) != 1 || pod.Spec.Volumes[0].PersistentVolumeClaim == nil || pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName != "src-pvc" {
+		t.Errorf("expected a PVC volume bound to src-pvc, got %+v", pod.Spec.Volumes)
+	}
+	if len(pod.Spec.Containers[0].VolumeMounts) != 1 || pod.Spec.Containers[0].VolumeMounts[0].MountPath != "/src" {
+		t.Errorf("expected the main container to mount it at /src, got %+v", pod.Spec.Containers[0].VolumeMounts)
+	}
+}
+
+func TestBuildPodRejectsInvalidResourceQuantity(t *testing.T) {
+	_, err := buildPod("t", sykli.TaskSpec{
+		Name: "t", Command: "echo hi",
+		K8s: &sykli.K8sTaskOptions{Resources: sykli.K8sResources{CPU: "not-a-quantity"}},
+	}, "alpine", nil)
+	if err == nil {
+		t.Fatal("expected an error for an invalid CPU quantity")
+	}
+}
+
+type fakeVolume struct{ id, ref string }
+
+func (v fakeVolume) ID() string        { return v.id }
+func (v fakeVolume) HostPath() string  { return "" }
+func (v fakeVolume) Reference() string { return v.ref }
@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	sykli "sykli.dev/go"
+)
+
+// advancePod drives fakeClient's sole Pod through Pending -> Running ->
+// phase, mimicking what a real kubelet would report, so RunTask's poll
+// loops have something to observe.
+func advancePod(t *testing.T, fakeClient *fake.Clientset, namespace string, phase corev1.PodPhase, exitCode int32) {
+	t.Helper()
+	go func() {
+		for {
+			pods, err := fakeClient.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil || len(pods.Items) == 0 {
+				time.Sleep(5 * time.Millisecond)
+				continue
+			}
+			pod := pods.Items[0]
+			pod.Status.Phase = corev1.PodRunning
+			fakeClient.CoreV1().Pods(namespace).UpdateStatus(context.Background(), &pod, metav1.UpdateOptions{})
+			time.Sleep(10 * time.Millisecond)
+
+			pod.Status.Phase = phase
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:  mainContainerName,
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode}},
+			}}
+			fakeClient.CoreV1().Pods(namespace).UpdateStatus(context.Background(), &pod, metav1.UpdateOptions{})
+			return
+		}
+	}()
+}
+
+func TestRunTaskSucceeds(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	target := &Target{Clientset: fakeClient, PollInterval: 5 * time.Millisecond}
+
+	advancePod(t, fakeClient, target.namespace(), corev1.PodSucceeded, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result := target.RunTask(ctx, sykli.TaskSpec{Name: "build", Command: "echo hi"})
+	if !result.Success || result.Error != nil {
+		t.Fatalf("expected success, got %+v", result)
+	}
+	if result.ExitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", result.ExitCode)
+	}
+
+	pods, err := fakeClient.CoreV1().Pods(target.namespace()).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 0 {
+		t.Errorf("expected RunTask to delete the pod after completion, found %d left", len(pods.Items))
+	}
+}
+
+func TestRunTaskReportsNonZeroExit(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	target := &Target{Clientset: fakeClient, PollInterval: 5 * time.Millisecond}
+
+	advancePod(t, fakeClient, target.namespace(), corev1.PodFailed, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result := target.RunTask(ctx, sykli.TaskSpec{Name: "build", Command: "exit 1"})
+	if result.Success {
+		t.Fatal("expected failure")
+	}
+	if result.ExitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", result.ExitCode)
+	}
+}
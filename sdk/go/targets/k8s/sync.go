@@ -0,0 +1,17 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// copyArtifact shells out to `kubectl cp src dst`, the same tar-over-exec
+// mechanism an init/sidecar container would need client-go's exec
+// subresource to replicate by hand.
+func copyArtifact(ctx context.Context, src, dst string) error {
+	if err := exec.CommandContext(ctx, "kubectl", "cp", src, dst).Run(); err != nil {
+		return fmt.Errorf("k8s: copying %q to %q: %w", src, dst, err)
+	}
+	return nil
+}
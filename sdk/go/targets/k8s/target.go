@@ -0,0 +1,218 @@
+// Package k8s runs sykli tasks as Kubernetes Pods via client-go, one Pod
+// per task: buildPod renders a task's command, env, mounts, and
+// K8sTaskOptions into a Pod spec, Target.RunTask creates it, follows its
+// logs, and waits for it to finish, deleting it afterward regardless of
+// outcome.
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	sykli "sykli.dev/go"
+)
+
+// Target runs sykli tasks as Kubernetes Pods. It implements sykli.Target,
+// sykli.Storage and sykli.Services; its StartServices and StopServices are
+// no-ops - see StartServices.
+type Target struct {
+	sykli.EnvSecrets
+
+	// Clientset talks to the cluster. Required.
+	Clientset kubernetes.Interface
+
+	// Namespace Pods are created in. Defaults to "default".
+	Namespace string
+
+	// Image is the container image used when a task doesn't set its own
+	// via Run/Container.
+	Image string
+
+	// PodTemplate, if set, seeds every Pod's spec before task-specific
+	// fields are layered on top - the way to set cluster-wide defaults
+	// (a default ServiceAccount, imagePullSecrets, a sidecar) that
+	// individual tasks' K8s(...) calls can still override.
+	PodTemplate *corev1.PodSpec
+
+	// PollInterval controls how often RunTask checks a Pod's status
+	// while waiting for it to finish. Defaults to 2 seconds.
+	PollInterval time.Duration
+}
+
+func (t *Target) namespace() string {
+	if t.Namespace != "" {
+		return t.Namespace
+	}
+	return "default"
+}
+
+func (t *Target) image() string {
+	if t.Image != "" {
+		return t.Image
+	}
+	return "alpine"
+}
+
+func (t *Target) pollInterval() time.Duration {
+	if t.PollInterval > 0 {
+		return t.PollInterval
+	}
+	return 2 * time.Second
+}
+
+// RunTask creates a Pod for task, streams its logs into Result.Output,
+// waits for it to finish, and deletes it - even if ctx is canceled or
+// RunTask returns early, since a leaked Pod would otherwise outlive the
+// task that spawned it.
+func (t *Target) RunTask(ctx context.Context, task sykli.TaskSpec) sykli.Result {
+	start := time.Now()
+	pods := t.Clientset.CoreV1().Pods(t.namespace())
+
+	pod, err := buildPod(task.Name, task, t.image(), t.PodTemplate)
+	if err != nil {
+		return sykli.Result{Success: false, Error: err, Duration: time.Since(start).Milliseconds()}
+	}
+
+	created, err := pods.Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return sykli.Result{Success: false, Error: fmt.Errorf("k8s: creating pod for task %q: %w", task.Name, err), Duration: time.Since(start).Milliseconds()}
+	}
+	defer func() {
+		// Use a background context: ctx may already be canceled (the
+		// reason RunTask is returning), but the Pod still needs deleting.
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = pods.Delete(cleanupCtx, created.Name, metav1.DeleteOptions{})
+	}()
+
+	if err := t.awaitRunning(ctx, pods, created.Name); err != nil {
+		return sykli.Result{Success: false, Error: err, Duration: time.Since(start).Milliseconds()}
+	}
+
+	var out bytes.Buffer
+	t.streamLogs(ctx, pods, created.Name, &out)
+
+	final, err := t.awaitCompletion(ctx, pods, created.Name)
+	if err != nil {
+		return sykli.Result{Output: out.String(), Error: err, Duration: time.Since(start).Milliseconds()}
+	}
+
+	result := sykli.Result{Output: out.String(), Duration: time.Since(start).Milliseconds()}
+	state := terminatedState(final)
+	if state == nil {
+		result.Error = fmt.Errorf("k8s: pod %q finished without a terminated container state", created.Name)
+		return result
+	}
+	result.ExitCode = int(state.ExitCode)
+	result.Success = state.ExitCode == 0
+	if !result.Success {
+		result.Error = fmt.Errorf("k8s: task %q exited %d: %s", task.Name, state.ExitCode, state.Reason)
+	}
+	return result
+}
+
+// awaitRunning polls until the Pod has left Pending, so streamLogs has a
+// container to attach to.
+func (t *Target) awaitRunning(ctx context.Context, pods corev1client.PodInterface, name string) error {
+	return wait.PollUntilContextCancel(ctx, t.pollInterval(), true, func(ctx context.Context) (bool, error) {
+		pod, err := pods.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("k8s: getting pod %q: %w", name, err)
+		}
+		return pod.Status.Phase != corev1.PodPending, nil
+	})
+}
+
+// awaitCompletion polls until the Pod has reached a terminal phase.
+func (t *Target) awaitCompletion(ctx context.Context, pods corev1client.PodInterface, name string) (*corev1.Pod, error) {
+	var final *corev1.Pod
+	err := wait.PollUntilContextCancel(ctx, t.pollInterval(), true, func(ctx context.Context) (bool, error) {
+		pod, err := pods.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("k8s: getting pod %q: %w", name, err)
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			final = pod
+			return true, nil
+		}
+		return false, nil
+	})
+	return final, err
+}
+
+// streamLogs follows the main container's logs into out, best-effort:
+// a log-streaming error doesn't fail the task, since the Pod's exit code
+// is what actually determines success.
+func (t *Target) streamLogs(ctx context.Context, pods corev1client.PodInterface, name string, out io.Writer) {
+	stream, err := pods.GetLogs(name, &corev1.PodLogOptions{Container: mainContainerName, Follow: true}).Stream(ctx)
+	if err != nil {
+		fmt.Fprintf(out, "k8s: attaching to logs: %v\n", err)
+		return
+	}
+	defer stream.Close()
+	io.Copy(out, stream)
+}
+
+func terminatedState(pod *corev1.Pod) *corev1.ContainerStateTerminated {
+	if pod == nil {
+		return nil
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == mainContainerName {
+			return cs.State.Terminated
+		}
+	}
+	return nil
+}
+
+// ArtifactPath returns the conventional path a task's artifacts land
+// under inside its Pod.
+func (t *Target) ArtifactPath(taskName, artifactName string) string {
+	return "/artifacts/" + taskName + "/" + artifactName
+}
+
+// CreateVolume creates nothing: Kubernetes PersistentVolumeClaims are
+// cluster resources this target expects to already exist (provisioned by
+// a StorageClass ahead of time), so CreateVolume just records name as
+// the claim to bind, matching how task.Mounts' Volume.Reference() is
+// read as a PVC claim name in buildPod.
+func (t *Target) CreateVolume(ctx context.Context, name string, opts sykli.VolumeOptions) (sykli.Volume, error) {
+	return pvcVolume{name: name}, nil
+}
+
+// CopyArtifact shells out to `kubectl cp`, since client-go has no
+// typed equivalent - copying into/out of a running Pod goes through the
+// exec subresource's tar streaming, which `kubectl cp` already wraps.
+// Requires a kubectl on PATH pointed at the same cluster as Clientset.
+func (t *Target) CopyArtifact(ctx context.Context, src, dst string) error {
+	return copyArtifact(ctx, src, dst)
+}
+
+// StartServices is a no-op: a sykli Service becomes a sidecar
+// container in the same Pod (see buildPod), reachable over localhost
+// once the Pod is running, not a separately managed process this target
+// starts or stops on its own.
+func (t *Target) StartServices(ctx context.Context, taskName string, services []sykli.ServiceSpec) (interface{}, error) {
+	return nil, nil
+}
+
+// StopServices is a no-op for the same reason as StartServices: sidecar
+// containers are torn down with the rest of the Pod in RunTask's cleanup.
+func (t *Target) StopServices(ctx context.Context, networkInfo interface{}) error {
+	return nil
+}
+
+type pvcVolume struct{ name string }
+
+func (v pvcVolume) ID() string        { return v.name }
+func (v pvcVolume) HostPath() string  { return "" }
+func (v pvcVolume) Reference() string { return v.name }
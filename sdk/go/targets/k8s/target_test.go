@@ -0,0 +1,32 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTargetDefaults(t *testing.T) {
+	var target Target
+	if target.namespace() != "default" {
+		t.Errorf("expected default namespace, got %q", target.namespace())
+	}
+	if target.image() != "alpine" {
+		t.Errorf("expected default image, got %q", target.image())
+	}
+	if target.pollInterval() != 2*time.Second {
+		t.Errorf("expected default poll interval of 2s, got %s", target.pollInterval())
+	}
+}
+
+func TestTargetOverrides(t *testing.T) {
+	target := Target{Namespace: "ci", Image: "golang:1.21", PollInterval: 500 * time.Millisecond}
+	if target.namespace() != "ci" {
+		t.Errorf("expected overridden namespace, got %q", target.namespace())
+	}
+	if target.image() != "golang:1.21" {
+		t.Errorf("expected overridden image, got %q", target.image())
+	}
+	if target.pollInterval() != 500*time.Millisecond {
+		t.Errorf("expected overridden poll interval, got %s", target.pollInterval())
+	}
+}
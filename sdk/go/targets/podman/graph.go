@@ -0,0 +1,37 @@
+// Package podman runs sykli tasks as podman containers and can emit the
+// task graph as systemd Quadlet unit files (man 5 podman-systemd.unit) so
+// a pipeline can be shipped as transient systemd services on a rootless
+// host, instead of being driven task-by-task through Target.RunTask.
+package podman
+
+// TaskNode is the plain-data view of one compiled sykli task that
+// EmitQuadlet needs to place it as a .container unit. It has no
+// knowledge of sykli.Pipeline or sykli.Task - sykli.Pipeline.PodmanGraph
+// builds a []TaskNode from its own unexported task state.
+type TaskNode struct {
+	Name      string
+	Command   string
+	Image     string
+	Workdir   string
+	Env       map[string]string
+	DependsOn []string
+
+	// Services become their own .container unit that this task's unit
+	// depends on via After=/Requires=.
+	Services []ServiceRef
+
+	// Caches become .volume units mounted into this task's unit.
+	Caches []CacheRef
+}
+
+// ServiceRef is the plain-data view of a sykli Service.
+type ServiceRef struct {
+	Name  string
+	Image string
+}
+
+// CacheRef is the plain-data view of a cache Mount.
+type CacheRef struct {
+	Name string
+	Path string
+}
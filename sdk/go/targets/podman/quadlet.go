@@ -0,0 +1,148 @@
+package podman
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// EmitQuadlet writes one .container unit per task, one .container unit
+// per unique service, and one .volume unit per unique cache mount into
+// dir, plus a .network unit if network is non-empty. Drop the result in
+// ~/.config/containers/systemd (rootless) or /etc/containers/systemd
+// (root) and `systemctl --user daemon-reload` (or `systemctl
+// daemon-reload`) picks them up as transient *.service units.
+func EmitQuadlet(nodes []TaskNode, dir string, network string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("podman: creating quadlet dir %q: %w", dir, err)
+	}
+
+	if network != "" {
+		if err := writeUnit(dir, network+".network", networkUnit()); err != nil {
+			return err
+		}
+	}
+
+	seenCaches := make(map[string]bool)
+	seenServices := make(map[string]bool)
+
+	for _, n := range nodes {
+		for _, c := range n.Caches {
+			if seenCaches[c.Name] {
+				continue
+			}
+			seenCaches[c.Name] = true
+			if err := writeUnit(dir, c.Name+".volume", volumeUnit()); err != nil {
+				return err
+			}
+		}
+		for _, svc := range n.Services {
+			if seenServices[svc.Name] {
+				continue
+			}
+			seenServices[svc.Name] = true
+			if err := writeUnit(dir, svc.Name+".container", serviceUnit(svc, network)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, n := range nodes {
+		if err := writeUnit(dir, n.Name+".container", taskUnit(n, network)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeUnit(dir, name, content string) error {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("podman: writing %q: %w", path, err)
+	}
+	return nil
+}
+
+func networkUnit() string {
+	return "[Network]\n"
+}
+
+func volumeUnit() string {
+	return "[Volume]\n"
+}
+
+func serviceUnit(svc ServiceRef, network string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Unit]\nDescription=sykli service %s\n\n", svc.Name)
+	b.WriteString("[Container]\n")
+	fmt.Fprintf(&b, "Image=%s\n", svc.Image)
+	fmt.Fprintf(&b, "ContainerName=%s\n", svc.Name)
+	if network != "" {
+		fmt.Fprintf(&b, "Network=%s.network\n", network)
+		fmt.Fprintf(&b, "NetworkAlias=%s\n", svc.Name)
+	}
+	b.WriteString("\n[Install]\nWantedBy=multi-user.target default.target\n")
+	return b.String()
+}
+
+func taskUnit(n TaskNode, network string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Unit]\nDescription=sykli task %s\n", n.Name)
+	for _, dep := range unitDeps(n) {
+		fmt.Fprintf(&b, "After=%s.service\nRequires=%s.service\n", dep, dep)
+	}
+
+	b.WriteString("\n[Container]\n")
+	fmt.Fprintf(&b, "Image=%s\n", n.Image)
+	fmt.Fprintf(&b, "ContainerName=%s\n", n.Name)
+	fmt.Fprintf(&b, "Exec=/bin/sh -c %q\n", n.Command)
+	if n.Workdir != "" {
+		fmt.Fprintf(&b, "WorkingDir=%s\n", n.Workdir)
+	}
+	for _, key := range sortedKeys(n.Env) {
+		fmt.Fprintf(&b, "Environment=%s=%s\n", key, n.Env[key])
+	}
+	for _, c := range n.Caches {
+		fmt.Fprintf(&b, "Volume=%s.volume:%s\n", c.Name, c.Path)
+	}
+	if network != "" {
+		fmt.Fprintf(&b, "Network=%s.network\n", network)
+	}
+
+	b.WriteString("\n[Service]\nRestart=no\n")
+	b.WriteString("\n[Install]\nWantedBy=multi-user.target default.target\n")
+	return b.String()
+}
+
+// unitDeps returns the sorted, deduped set of units this task's unit must
+// start After= and Requires=: its DependsOn tasks, plus any Service it
+// uses.
+func unitDeps(n TaskNode) []string {
+	deps := append([]string(nil), n.DependsOn...)
+	for _, svc := range n.Services {
+		deps = appendIfMissing(deps, svc.Name)
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func appendIfMissing(list []string, item string) []string {
+	for _, existing := range list {
+		if existing == item {
+			return list
+		}
+	}
+	return append(list, item)
+}
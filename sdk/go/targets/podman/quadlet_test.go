@@ -0,0 +1,93 @@
+package podman
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmitQuadletWritesOneContainerUnitPerTask(t *testing.T) {
+	dir := t.TempDir()
+	nodes := []TaskNode{
+		{Name: "build", Command: "go build ./...", Image: "golang:1.21"},
+		{Name: "test", Command: "go test ./...", Image: "golang:1.21", DependsOn: []string{"build"}},
+	}
+	if err := EmitQuadlet(nodes, dir, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	testUnit := readUnit(t, dir, "test.container")
+	if !strings.Contains(testUnit, "After=build.service") || !strings.Contains(testUnit, "Requires=build.service") {
+		t.Errorf("expected test.container to depend on build.service, got:\n%s", testUnit)
+	}
+	if !strings.Contains(testUnit, "Image=golang:1.21") {
+		t.Errorf("expected test.container to set Image, got:\n%s", testUnit)
+	}
+}
+
+func TestEmitQuadletWritesServiceAndVolumeUnitsOncePerName(t *testing.T) {
+	dir := t.TempDir()
+	nodes := []TaskNode{
+		{
+			Name: "integration", Command: "go test -tags=integration ./...", Image: "golang:1.21",
+			Services: []ServiceRef{{Name: "postgres", Image: "postgres:16"}},
+			Caches:   []CacheRef{{Name: "go-mod", Path: "/go/pkg/mod"}},
+		},
+		{
+			Name: "integration2", Command: "go test -tags=integration2 ./...", Image: "golang:1.21",
+			Services: []ServiceRef{{Name: "postgres", Image: "postgres:16"}},
+			Caches:   []CacheRef{{Name: "go-mod", Path: "/go/pkg/mod"}},
+		},
+	}
+	if err := EmitQuadlet(nodes, dir, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := map[string]int{}
+	for _, e := range entries {
+		count[e.Name()]++
+	}
+	if count["postgres.container"] != 1 {
+		t.Errorf("expected exactly one postgres.container unit, got %d", count["postgres.container"])
+	}
+	if count["go-mod.volume"] != 1 {
+		t.Errorf("expected exactly one go-mod.volume unit, got %d", count["go-mod.volume"])
+	}
+
+	task := readUnit(t, dir, "integration.container")
+	if !strings.Contains(task, "After=postgres.service") {
+		t.Errorf("expected task unit to depend on its service, got:\n%s", task)
+	}
+	if !strings.Contains(task, "Volume=go-mod.volume:/go/pkg/mod") {
+		t.Errorf("expected task unit to mount the cache volume, got:\n%s", task)
+	}
+}
+
+func TestEmitQuadletWritesNetworkUnitWhenSet(t *testing.T) {
+	dir := t.TempDir()
+	nodes := []TaskNode{{Name: "build", Command: "echo hi", Image: "alpine"}}
+	if err := EmitQuadlet(nodes, dir, "sykli"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sykli.network")); err != nil {
+		t.Errorf("expected sykli.network unit to be written: %v", err)
+	}
+	task := readUnit(t, dir, "build.container")
+	if !strings.Contains(task, "Network=sykli.network") {
+		t.Errorf("expected task unit to join sykli.network, got:\n%s", task)
+	}
+}
+
+func readUnit(t *testing.T, dir, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("reading %s: %v", name, err)
+	}
+	return string(b)
+}
@@ -0,0 +1,220 @@
+// Package runner implements sykli.Target by shelling out to the podman
+// CLI. It is split out from the sibling targets/podman package (which
+// only emits Quadlet manifests as plain data, with no dependency on
+// sykli) because Target must import sykli.dev/go to satisfy
+// sykli.Target/sykli.TaskSpec/sykli.Result - importing it from
+// targets/podman itself would create an import cycle, since the root
+// sykli package imports targets/podman back for Pipeline.PodmanGraph.
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	sykli "sykli.dev/go"
+	"sykli.dev/go/targets/podman"
+)
+
+// Target runs sykli tasks as podman containers via the podman CLI. It
+// implements sykli.Target, sykli.Lifecycle, sykli.Storage, and
+// sykli.Services directly; embed PodmanSecrets instead of the default
+// sykli.EnvSecrets to resolve secrets from podman's own encrypted store.
+type Target struct {
+	sykli.EnvSecrets
+
+	// Binary is the podman executable to shell out to. Defaults to "podman".
+	Binary string
+
+	// Network is the shared podman network tasks and services join so
+	// services are reachable by name, matching sykli's in-process model
+	// where a Service's Name doubles as its hostname.
+	Network string
+
+	volumes map[string]string // name -> podman volume name, for Storage
+}
+
+func (t *Target) binary() string {
+	if t.Binary != "" {
+		return t.Binary
+	}
+	return "podman"
+}
+
+// Rootless reports whether this process should drive podman in rootless
+// mode, detected the same way podman itself does: XDG_RUNTIME_DIR set.
+func Rootless() bool {
+	return os.Getenv("XDG_RUNTIME_DIR") != ""
+}
+
+// Setup creates t.Network if it doesn't already exist.
+func (t *Target) Setup(ctx context.Context) error {
+	if t.Network == "" {
+		return nil
+	}
+	if err := exec.CommandContext(ctx, t.binary(), "network", "exists", t.Network).Run(); err == nil {
+		return nil
+	}
+	if err := exec.CommandContext(ctx, t.binary(), "network", "create", t.Network).Run(); err != nil {
+		return fmt.Errorf("podman: creating network %q: %w", t.Network, err)
+	}
+	return nil
+}
+
+// Teardown is a no-op: the shared network and any volumes outlive a
+// single pipeline run so later runs can reuse them.
+func (t *Target) Teardown(ctx context.Context) error {
+	return nil
+}
+
+// RunTask runs task as `podman run --rm`, wiring its env, mounts, and the
+// shared network (if set).
+func (t *Target) RunTask(ctx context.Context, task sykli.TaskSpec) sykli.Result {
+	start := time.Now()
+
+	args := []string{"run", "--rm"}
+	if t.Network != "" {
+		args = append(args, "--network", t.Network)
+	}
+	if task.Workdir != "" {
+		args = append(args, "--workdir", task.Workdir)
+	}
+	for _, key := range sortedKeys(task.Env) {
+		args = append(args, "--env", key+"="+task.Env[key])
+	}
+	for _, m := range task.Mounts {
+		if m.Volume == nil {
+			continue
+		}
+		args = append(args, "--volume", m.Volume.Reference()+":"+m.Path)
+	}
+	args = append(args, task.Image, "/bin/sh", "-c", task.Command)
+
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.binary(), args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+
+	result := sykli.Result{Output: out.String(), Duration: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Error = fmt.Errorf("podman: running task %q: %w", task.Name, err)
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			result.ExitCode = exitErr.ExitCode()
+		}
+		return result
+	}
+	result.Success = true
+	return result
+}
+
+// EmitQuadlet writes nodes as systemd Quadlet unit files under dir using
+// t.Network, instead of running them directly.
+func (t *Target) EmitQuadlet(dir string, nodes []podman.TaskNode) error {
+	return podman.EmitQuadlet(nodes, dir, t.Network)
+}
+
+// CreateVolume creates a podman named volume for name.
+func (t *Target) CreateVolume(ctx context.Context, name string, opts sykli.VolumeOptions) (sykli.Volume, error) {
+	volName := "sykli-" + name
+	if err := exec.CommandContext(ctx, t.binary(), "volume", "create", volName).Run(); err != nil {
+		return nil, fmt.Errorf("podman: creating volume %q: %w", volName, err)
+	}
+	if t.volumes == nil {
+		t.volumes = make(map[string]string)
+	}
+	t.volumes[name] = volName
+	return podmanVolume{name: volName}, nil
+}
+
+// ArtifactPath returns the conventional in-container path a task's
+// artifacts land under.
+func (t *Target) ArtifactPath(taskName, artifactName string) string {
+	return filepath.Join("/artifacts", taskName, artifactName)
+}
+
+// CopyArtifact copies a file out of (or into) a container via `podman cp`.
+func (t *Target) CopyArtifact(ctx context.Context, src, dst string) error {
+	if err := exec.CommandContext(ctx, t.binary(), "cp", src, dst).Run(); err != nil {
+		return fmt.Errorf("podman: copying %q to %q: %w", src, dst, err)
+	}
+	return nil
+}
+
+// StartServices starts each service as its own detached container on
+// t.Network, aliased to its name so tasks can reach it by hostname.
+func (t *Target) StartServices(ctx context.Context, taskName string, services []sykli.ServiceSpec) (interface{}, error) {
+	started := make([]string, 0, len(services))
+	for _, svc := range services {
+		containerName := taskName + "-" + svc.Name
+		args := []string{"run", "-d", "--rm", "--name", containerName}
+		if t.Network != "" {
+			args = append(args, "--network", t.Network, "--network-alias", svc.Name)
+		}
+		args = append(args, svc.Image)
+		if err := exec.CommandContext(ctx, t.binary(), args...).Run(); err != nil {
+			return nil, fmt.Errorf("podman: starting service %q: %w", svc.Name, err)
+		}
+		started = append(started, containerName)
+	}
+	return started, nil
+}
+
+// StopServices removes the containers StartServices started.
+func (t *Target) StopServices(ctx context.Context, networkInfo interface{}) error {
+	names, ok := networkInfo.([]string)
+	if !ok {
+		return nil
+	}
+	for _, name := range names {
+		if err := exec.CommandContext(ctx, t.binary(), "rm", "-f", name).Run(); err != nil {
+			return fmt.Errorf("podman: stopping service container %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+type podmanVolume struct{ name string }
+
+func (v podmanVolume) ID() string        { return v.name }
+func (v podmanVolume) HostPath() string  { return "" }
+func (v podmanVolume) Reference() string { return v.name }
+
+// PodmanSecrets resolves secrets from podman's own encrypted-at-rest
+// secret store via `podman secret`, for targets that want that instead of
+// sykli.EnvSecrets.
+type PodmanSecrets struct {
+	// Binary is the podman executable to shell out to. Defaults to "podman".
+	Binary string
+}
+
+func (s PodmanSecrets) binary() string {
+	if s.Binary != "" {
+		return s.Binary
+	}
+	return "podman"
+}
+
+// ResolveSecret reads name's value via `podman secret inspect --showsecret`.
+func (s PodmanSecrets) ResolveSecret(ctx context.Context, name string) (string, error) {
+	out, err := exec.CommandContext(ctx, s.binary(), "secret", "inspect", "--showsecret", "--format", "{{.SecretData}}", name).Output()
+	if err != nil {
+		return "", fmt.Errorf("podman: resolving secret %q: %w", name, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
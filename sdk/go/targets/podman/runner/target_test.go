@@ -0,0 +1,25 @@
+package runner
+
+import "testing"
+
+func TestRootlessReflectsXDGRuntimeDir(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	if Rootless() {
+		t.Error("expected Rootless to be false with XDG_RUNTIME_DIR unset")
+	}
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	if !Rootless() {
+		t.Error("expected Rootless to be true with XDG_RUNTIME_DIR set")
+	}
+}
+
+func TestTargetDefaultsBinaryToPodman(t *testing.T) {
+	var target Target
+	if target.binary() != "podman" {
+		t.Errorf("expected default binary to be podman, got %q", target.binary())
+	}
+	target.Binary = "podman-remote"
+	if target.binary() != "podman-remote" {
+		t.Errorf("expected overridden binary, got %q", target.binary())
+	}
+}
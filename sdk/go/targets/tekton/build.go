@@ -0,0 +1,196 @@
+package tekton
+
+import (
+	"fmt"
+	"sort"
+)
+
+// BuildOptions configures the Pipeline/PipelineRun names Build produces
+// and how its workspaces are bound to real volumes.
+type BuildOptions struct {
+	// Name is used for the Pipeline; the PipelineRun is named Name+"-run".
+	Name string
+
+	Namespace string
+
+	// PVCClaimNames maps a workspace name (TaskNode.Workspace.Name) to an
+	// existing PersistentVolumeClaim to bind it to. A workspace with no
+	// entry here is bound to an ephemeral emptyDir instead.
+	PVCClaimNames map[string]string
+}
+
+// Build translates nodes into a Tekton Pipeline and a PipelineRun that
+// executes it. Callers should run Validate first; apart from a missing
+// BuildOptions.Name, Build itself only returns an error for constructs it
+// can't lower (an unsupported When condition), surfaced as a
+// *TektonUnsupportedError.
+func Build(nodes []TaskNode, opts BuildOptions) (*Pipeline, *PipelineRun, error) {
+	if opts.Name == "" {
+		return nil, nil, fmt.Errorf("tekton: BuildOptions.Name is required")
+	}
+
+	workspaceSeen := make(map[string]bool)
+	var pipelineWorkspaces []WorkspacePipelineDecl
+
+	tasks := make([]PipelineTask, 0, len(nodes))
+	var taskRunSpecs []PipelineTaskRunSpec
+
+	for _, n := range nodes {
+		when, err := lowerWhen(n.When)
+		if err != nil {
+			return nil, nil, &TektonUnsupportedError{Task: n.Name, Reason: err.Error()}
+		}
+
+		pt := PipelineTask{
+			Name:     n.Name,
+			RunAfter: append([]string(nil), n.DependsOn...),
+			When:     when,
+		}
+
+		for _, ti := range n.TaskInputs {
+			pt.Params = append(pt.Params, Param{
+				Name:  ti.OutputName,
+				Value: fmt.Sprintf("$(tasks.%s.results.%s)", ti.FromTask, ti.OutputName),
+			})
+			pt.RunAfter = appendIfMissing(pt.RunAfter, ti.FromTask)
+		}
+
+		if n.Workspace != nil {
+			pt.Workspaces = []WorkspaceBinding{{Name: n.Workspace.Name}}
+			if !workspaceSeen[n.Workspace.Name] {
+				workspaceSeen[n.Workspace.Name] = true
+				pipelineWorkspaces = append(pipelineWorkspaces, WorkspacePipelineDecl{Name: n.Workspace.Name})
+			}
+		}
+
+		if len(n.Matrix) > 0 {
+			pt.Matrix = &Matrix{Params: matrixParams(n.Matrix)}
+		}
+
+		pt.TaskSpec = embeddedTask(n)
+		tasks = append(tasks, pt)
+
+		if rs := taskRunSpec(n); rs != nil {
+			taskRunSpecs = append(taskRunSpecs, *rs)
+		}
+	}
+
+	pipeline := &Pipeline{
+		TypeMeta:   TypeMeta{APIVersion: "tekton.dev/v1", Kind: "Pipeline"},
+		ObjectMeta: ObjectMeta{Name: opts.Name, Namespace: opts.Namespace},
+		Spec:       PipelineSpec{Tasks: tasks, Workspaces: pipelineWorkspaces},
+	}
+
+	run := &PipelineRun{
+		TypeMeta:   TypeMeta{APIVersion: "tekton.dev/v1", Kind: "PipelineRun"},
+		ObjectMeta: ObjectMeta{Name: opts.Name + "-run", Namespace: opts.Namespace},
+		Spec: PipelineRunSpec{
+			PipelineRef:  &PipelineRef{Name: opts.Name},
+			Workspaces:   workspaceBindings(pipelineWorkspaces, opts.PVCClaimNames),
+			TaskRunSpecs: taskRunSpecs,
+		},
+	}
+
+	return pipeline, run, nil
+}
+
+func embeddedTask(n TaskNode) *EmbeddedTask {
+	embedded := &EmbeddedTask{
+		Steps: []Step{{
+			Name:       "run",
+			Image:      n.Image,
+			Script:     "#!/bin/sh\nset -e\n" + n.Command,
+			WorkingDir: n.Workdir,
+			Env:        sortedEnv(n.Env),
+		}},
+	}
+
+	for outName := range n.Outputs {
+		embedded.Results = append(embedded.Results, TaskResult{Name: outName})
+	}
+	sort.Slice(embedded.Results, func(i, j int) bool { return embedded.Results[i].Name < embedded.Results[j].Name })
+
+	for _, ti := range n.TaskInputs {
+		embedded.Params = append(embedded.Params, ParamSpec{Name: ti.OutputName})
+	}
+
+	if n.Workspace != nil {
+		embedded.Workspaces = []WorkspaceTaskDecl{{Name: n.Workspace.Name}}
+	}
+
+	for _, svc := range n.Services {
+		embedded.Sidecars = append(embedded.Sidecars, Sidecar{Name: svc.Name, Image: svc.Image})
+	}
+
+	return embedded
+}
+
+func taskRunSpec(n TaskNode) *PipelineTaskRunSpec {
+	if n.K8s == nil {
+		return nil
+	}
+	rs := &PipelineTaskRunSpec{
+		PipelineTaskName:   n.Name,
+		ServiceAccountName: n.K8s.ServiceAccount,
+	}
+	if len(n.K8s.NodeSelector) > 0 || len(n.K8s.Tolerations) > 0 {
+		pod := &PodTemplate{NodeSelector: n.K8s.NodeSelector}
+		for _, tol := range n.K8s.Tolerations {
+			pod.Tolerations = append(pod.Tolerations, Toleration{
+				Key:      tol.Key,
+				Operator: tol.Operator,
+				Value:    tol.Value,
+				Effect:   tol.Effect,
+			})
+		}
+		rs.PodTemplate = pod
+	}
+	return rs
+}
+
+func workspaceBindings(decls []WorkspacePipelineDecl, pvcClaimNames map[string]string) []WorkspaceBinding {
+	bindings := make([]WorkspaceBinding, len(decls))
+	for i, d := range decls {
+		if claim, ok := pvcClaimNames[d.Name]; ok {
+			bindings[i] = WorkspaceBinding{Name: d.Name, PersistentVolumeClaim: &PVCVolumeSource{ClaimName: claim}}
+		} else {
+			bindings[i] = WorkspaceBinding{Name: d.Name, EmptyDir: &EmptyDirVolumeSource{}}
+		}
+	}
+	return bindings
+}
+
+func matrixParams(matrix map[string][]string) []MatrixParam {
+	keys := make([]string, 0, len(matrix))
+	for k := range matrix {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	params := make([]MatrixParam, len(keys))
+	for i, k := range keys {
+		params[i] = MatrixParam{Name: k, Value: matrix[k]}
+	}
+	return params
+}
+
+func sortedEnv(env map[string]string) []EnvVar {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	vars := make([]EnvVar, len(keys))
+	for i, k := range keys {
+		vars[i] = EnvVar{Name: k, Value: env[k]}
+	}
+	return vars
+}
+
+func appendIfMissing(list []string, item string) []string {
+	for _, existing := range list {
+		if existing == item {
+			return list
+		}
+	}
+	return append(list, item)
+}
@@ -0,0 +1,96 @@
+package tekton
+
+import "testing"
+
+func TestBuildSetsRunAfterFromDependsOn(t *testing.T) {
+	nodes := []TaskNode{
+		{Name: "build", Command: "go build ./...", Image: "golang:1.21"},
+		{Name: "test", Command: "go test ./...", Image: "golang:1.21", DependsOn: []string{"build"}},
+	}
+	p, _, err := Build(nodes, BuildOptions{Name: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Spec.Tasks[1].Name != "test" || len(p.Spec.Tasks[1].RunAfter) != 1 || p.Spec.Tasks[1].RunAfter[0] != "build" {
+		t.Errorf("expected test to run after build, got %+v", p.Spec.Tasks[1])
+	}
+}
+
+func TestBuildWiresTaskInputParamsAndRunAfter(t *testing.T) {
+	nodes := []TaskNode{
+		{Name: "build", Command: "go build -o /out/app", Image: "golang:1.21", Outputs: map[string]string{"binary": "/out/app"}},
+		{Name: "package", Command: "./package.sh", Image: "alpine", TaskInputs: []TaskInputRef{{FromTask: "build", OutputName: "binary", DestPath: "/app"}}},
+	}
+	p, _, err := Build(nodes, BuildOptions{Name: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := p.Spec.Tasks[1]
+	if len(pkg.Params) != 1 || pkg.Params[0].Value != "$(tasks.build.results.binary)" {
+		t.Errorf("expected param wired to build's result, got %+v", pkg.Params)
+	}
+	if len(pkg.RunAfter) != 1 || pkg.RunAfter[0] != "build" {
+		t.Errorf("expected runAfter to include build, got %+v", pkg.RunAfter)
+	}
+	if len(p.Spec.Tasks[0].TaskSpec.Results) != 1 || p.Spec.Tasks[0].TaskSpec.Results[0].Name != "binary" {
+		t.Errorf("expected build's taskSpec to declare a binary result, got %+v", p.Spec.Tasks[0].TaskSpec.Results)
+	}
+}
+
+func TestBuildMatrixBecomesMatrixParams(t *testing.T) {
+	nodes := []TaskNode{{Name: "test", Command: "go test", Image: "golang", Matrix: map[string][]string{"os": {"linux", "darwin"}}}}
+	p, _, err := Build(nodes, BuildOptions{Name: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := p.Spec.Tasks[0].Matrix
+	if m == nil || len(m.Params) != 1 || m.Params[0].Name != "os" || len(m.Params[0].Value) != 2 {
+		t.Errorf("expected matrix param os with 2 values, got %+v", m)
+	}
+}
+
+func TestBuildWorkspaceDedupedAcrossTasks(t *testing.T) {
+	nodes := []TaskNode{
+		{Name: "a", Command: "echo a", Image: "alpine", Workspace: &WorkspaceRef{Name: "src", Path: "/src", Kind: "directory"}},
+		{Name: "b", Command: "echo b", Image: "alpine", Workspace: &WorkspaceRef{Name: "src", Path: "/src", Kind: "directory"}},
+	}
+	p, run, err := Build(nodes, BuildOptions{Name: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Spec.Workspaces) != 1 || p.Spec.Workspaces[0].Name != "src" {
+		t.Errorf("expected exactly one deduped pipeline workspace, got %+v", p.Spec.Workspaces)
+	}
+	if len(run.Spec.Workspaces) != 1 || run.Spec.Workspaces[0].EmptyDir == nil {
+		t.Errorf("expected the run to bind the workspace to an emptyDir by default, got %+v", run.Spec.Workspaces)
+	}
+}
+
+func TestBuildBindsWorkspaceToPVCWhenConfigured(t *testing.T) {
+	nodes := []TaskNode{{Name: "a", Command: "echo a", Image: "alpine", Workspace: &WorkspaceRef{Name: "src", Path: "/src", Kind: "directory"}}}
+	_, run, err := Build(nodes, BuildOptions{Name: "ci", PVCClaimNames: map[string]string{"src": "src-claim"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Spec.Workspaces[0].PersistentVolumeClaim == nil || run.Spec.Workspaces[0].PersistentVolumeClaim.ClaimName != "src-claim" {
+		t.Errorf("expected workspace bound to PVC src-claim, got %+v", run.Spec.Workspaces[0])
+	}
+}
+
+func TestBuildTaskRunSpecCarriesK8sOptions(t *testing.T) {
+	nodes := []TaskNode{{
+		Name: "build", Command: "echo hi", Image: "alpine",
+		K8s: &K8sOptions{ServiceAccount: "ci-bot", NodeSelector: map[string]string{"disk": "ssd"}},
+	}}
+	_, run, err := Build(nodes, BuildOptions{Name: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(run.Spec.TaskRunSpecs) != 1 {
+		t.Fatalf("expected one taskRunSpec, got %d", len(run.Spec.TaskRunSpecs))
+	}
+	rs := run.Spec.TaskRunSpecs[0]
+	if rs.ServiceAccountName != "ci-bot" || rs.PodTemplate == nil || rs.PodTemplate.NodeSelector["disk"] != "ssd" {
+		t.Errorf("expected K8s options mapped onto podTemplate, got %+v", rs)
+	}
+}
@@ -0,0 +1,78 @@
+package tekton
+
+// TaskNode is the plain-data view of one compiled sykli task that Build
+// needs in order to place it in a Tekton Pipeline. It has no knowledge of
+// sykli.Pipeline or sykli.Task - sykli.Pipeline.TektonPipeline builds a
+// []TaskNode from its own unexported task state and hands it to Build.
+type TaskNode struct {
+	Name      string
+	Command   string
+	Image     string
+	Workdir   string
+	Env       map[string]string
+	DependsOn []string
+
+	// Outputs maps an output name to its path, becoming a Tekton result.
+	Outputs map[string]string
+
+	// TaskInputs become params wired to $(tasks.<FromTask>.results.<OutputName>).
+	TaskInputs []TaskInputRef
+
+	// Services become sidecars on the embedded taskSpec.
+	Services []ServiceRef
+
+	// Workspace is the directory or cache mount this task needs, if any.
+	// Only one workspace per task is supported; a task with more than one
+	// directory/cache mount uses the first and ignores the rest.
+	Workspace *WorkspaceRef
+
+	// Matrix becomes the PipelineTask's matrix field. Dimensions whose
+	// task used MatrixExclude/MatrixInclude must be rejected before
+	// reaching Build - Tekton's matrix field can't express either.
+	Matrix map[string][]string
+
+	// When is the raw sykli when-condition source. Build lowers it to
+	// Tekton when expressions, or fails with a *TektonUnsupportedError if
+	// it can't.
+	When string
+
+	// K8s carries the subset of K8sTaskOptions Build can translate onto
+	// taskRunSpecs[].podTemplate.
+	K8s *K8sOptions
+}
+
+// TaskInputRef is the plain-data view of a sykli TaskInput.
+type TaskInputRef struct {
+	FromTask   string
+	OutputName string
+	DestPath   string
+}
+
+// ServiceRef is the plain-data view of a sykli Service.
+type ServiceRef struct {
+	Name  string
+	Image string
+}
+
+// WorkspaceRef is the plain-data view of a directory or cache Mount.
+type WorkspaceRef struct {
+	Name string
+	Path string
+	Kind string // "directory" or "cache"
+}
+
+// K8sOptions is the plain-data view of a sykli K8sTaskOptions that Build
+// can express through Tekton's taskRunSpecs[].podTemplate.
+type K8sOptions struct {
+	NodeSelector   map[string]string
+	ServiceAccount string
+	Tolerations    []K8sToleration
+}
+
+// K8sToleration is the plain-data view of a sykli K8sToleration.
+type K8sToleration struct {
+	Key      string
+	Operator string
+	Value    string
+	Effect   string
+}
@@ -0,0 +1,244 @@
+// Package runner implements sykli.Target on top of the sibling
+// targets/tekton package's pure Build/Validate conversion. It lives in
+// its own package, rather than inside targets/tekton itself, because
+// Target must import sykli.dev/go to satisfy sykli.Target/sykli.TaskSpec
+// /sykli.Result - importing it from targets/tekton would create an
+// import cycle, since the root sykli package imports targets/tekton back
+// for Pipeline.TektonPipeline.
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+
+	sykli "sykli.dev/go"
+	"sykli.dev/go/targets/tekton"
+)
+
+var (
+	pipelineGVR    = schema.GroupVersionResource{Group: "tekton.dev", Version: "v1", Resource: "pipelines"}
+	pipelineRunGVR = schema.GroupVersionResource{Group: "tekton.dev", Version: "v1", Resource: "pipelineruns"}
+)
+
+// Target runs sykli pipelines by translating them into Tekton Pipeline +
+// PipelineRun CRDs.
+//
+// RunTask satisfies the sykli.Target interface's one required method, but
+// only at the granularity of a single task - it builds and applies (or
+// prints) an ad hoc one-task Pipeline, with no runAfter/workspace linkage
+// to other tasks. Tekton's actual strength is a whole-DAG Pipeline built
+// from every task at once, so prefer calling
+// sykli.Pipeline.TektonPipeline directly plus Target.Apply over driving
+// this target task-by-task.
+type Target struct {
+	sykli.EnvSecrets
+
+	Namespace      string
+	ServiceAccount string
+	PVCClaimNames  map[string]string
+
+	// Dynamic is the client-go dynamic client used to apply and watch
+	// manifests. Leave nil for dry-run: RunTask and Apply write the
+	// rendered YAML to Out instead of touching a cluster.
+	Dynamic dynamic.Interface
+	Out     io.Writer
+
+	// PollInterval controls how often Apply checks a PipelineRun's status
+	// while waiting for it to finish. Defaults to 2 seconds.
+	PollInterval time.Duration
+}
+
+// RunTask builds a one-task Pipeline/PipelineRun for task and either
+// applies it and waits for completion (Dynamic set) or writes its YAML to
+// Out (Dynamic nil).
+func (t *Target) RunTask(ctx context.Context, task sykli.TaskSpec) sykli.Result {
+	start := time.Now()
+
+	node := tekton.TaskNode{
+		Name:    task.Name,
+		Command: task.Command,
+		Image:   task.Image,
+		Workdir: task.Workdir,
+		Env:     task.Env,
+	}
+	for _, svc := range task.Services {
+		node.Services = append(node.Services, tekton.ServiceRef{Name: svc.Name, Image: svc.Image})
+	}
+	if task.K8s != nil {
+		node.K8s = &tekton.K8sOptions{NodeSelector: task.K8s.NodeSelector, ServiceAccount: task.K8s.ServiceAccount}
+		for _, tol := range task.K8s.Tolerations {
+			node.K8s.Tolerations = append(node.K8s.Tolerations, tekton.K8sToleration{
+				Key: tol.Key, Operator: tol.Operator, Value: tol.Value, Effect: tol.Effect,
+			})
+		}
+	}
+
+	pipeline, run, err := tekton.Build([]tekton.TaskNode{node}, tekton.BuildOptions{
+		Name:          task.Name,
+		Namespace:     t.Namespace,
+		PVCClaimNames: t.PVCClaimNames,
+	})
+	if err != nil {
+		return sykli.Result{Success: false, Error: fmt.Errorf("tekton: %w", err), Duration: time.Since(start).Milliseconds()}
+	}
+
+	if err := t.Apply(ctx, pipeline, run); err != nil {
+		return sykli.Result{Success: false, Error: err, Duration: time.Since(start).Milliseconds()}
+	}
+
+	return sykli.Result{Success: true, Duration: time.Since(start).Milliseconds()}
+}
+
+// Apply submits pipeline and run to the cluster via Dynamic and waits for
+// the PipelineRun to finish, or - if Dynamic is nil - writes both
+// manifests as YAML to Out.
+func (t *Target) Apply(ctx context.Context, pipeline *tekton.Pipeline, run *tekton.PipelineRun) error {
+	if t.Dynamic == nil {
+		return t.render(pipeline, run)
+	}
+
+	pipelineUnstructured, err := toUnstructured(pipeline)
+	if err != nil {
+		return fmt.Errorf("tekton: converting Pipeline %q: %w", pipeline.Name, err)
+	}
+	if _, err := t.Dynamic.Resource(pipelineGVR).Namespace(t.Namespace).Create(ctx, pipelineUnstructured, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("tekton: applying Pipeline %q: %w", pipeline.Name, err)
+	}
+
+	runUnstructured, err := toUnstructured(run)
+	if err != nil {
+		return fmt.Errorf("tekton: converting PipelineRun %q: %w", run.Name, err)
+	}
+	if _, err := t.Dynamic.Resource(pipelineRunGVR).Namespace(t.Namespace).Create(ctx, runUnstructured, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("tekton: applying PipelineRun %q: %w", run.Name, err)
+	}
+
+	return t.waitForCompletion(ctx, run.Name)
+}
+
+func (t *Target) render(pipeline *tekton.Pipeline, run *tekton.PipelineRun) error {
+	out := t.Out
+	if out == nil {
+		return fmt.Errorf("tekton: dry-run requires Target.Out to be set")
+	}
+	for _, doc := range []interface{}{pipeline, run} {
+		b, err := yaml.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("tekton: marshaling manifest: %w", err)
+		}
+		if _, err := out.Write(append(b, []byte("---\n")...)); err != nil {
+			return fmt.Errorf("tekton: writing manifest: %w", err)
+		}
+	}
+	return nil
+}
+
+// waitForCompletion polls the PipelineRun named name until its Succeeded
+// condition is reported, or ctx is done.
+func (t *Target) waitForCompletion(ctx context.Context, name string) error {
+	interval := t.PollInterval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	return wait.PollUntilContextCancel(ctx, interval, true, func(ctx context.Context) (bool, error) {
+		obj, err := t.Dynamic.Resource(pipelineRunGVR).Namespace(t.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("tekton: getting PipelineRun %q: %w", name, err)
+		}
+		status, found := pipelineRunSucceeded(obj)
+		if !found {
+			return false, nil
+		}
+		if !status {
+			return false, fmt.Errorf("tekton: PipelineRun %q did not succeed", name)
+		}
+		return true, nil
+	})
+}
+
+func pipelineRunSucceeded(obj *unstructured.Unstructured) (succeeded, found bool) {
+	conditions, ok, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !ok {
+		return false, false
+	}
+	for _, c := range conditions {
+		condMap, ok := c.(map[string]interface{})
+		if !ok || condMap["type"] != "Succeeded" {
+			continue
+		}
+		status, _ := condMap["status"].(string)
+		switch status {
+		case "True":
+			return true, true
+		case "False":
+			return false, true
+		default:
+			return false, false
+		}
+	}
+	return false, false
+}
+
+func toUnstructured(v interface{}) (*unstructured.Unstructured, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic map[string]interface{}
+	if err := yaml.Unmarshal(b, &generic); err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: generic}, nil
+}
+
+// ArtifactPath returns the conventional workspace path a task's artifacts
+// land under when the shared workspace is bound into every task.
+func (t *Target) ArtifactPath(taskName, artifactName string) string {
+	return fmt.Sprintf("/workspace/output/%s/%s", taskName, artifactName)
+}
+
+// CreateVolume records name as the claim name for the workspace of the same
+// name, so later Build calls bind that workspace to the existing
+// PersistentVolumeClaim called name instead of an emptyDir.
+func (t *Target) CreateVolume(ctx context.Context, name string, opts sykli.VolumeOptions) (sykli.Volume, error) {
+	if t.PVCClaimNames == nil {
+		t.PVCClaimNames = make(map[string]string)
+	}
+	t.PVCClaimNames[name] = name
+	return pvcVolume{name: name}, nil
+}
+
+// CopyArtifact is not implemented: artifacts move between Tekton tasks
+// through results and shared workspaces, resolved when the Pipeline is
+// built, not by copying files on the target's behalf at run time.
+func (t *Target) CopyArtifact(ctx context.Context, src, dst string) error {
+	return fmt.Errorf("tekton: CopyArtifact is not supported; artifacts move via Tekton results/workspaces, wired in at Build time")
+}
+
+// StartServices is a no-op: sykli Service containers are already lowered
+// to Tekton sidecars when the Pipeline is built, so there is nothing left
+// to start at task-run time.
+func (t *Target) StartServices(ctx context.Context, taskName string, services []sykli.ServiceSpec) (interface{}, error) {
+	return nil, nil
+}
+
+// StopServices is a no-op for the same reason as StartServices.
+func (t *Target) StopServices(ctx context.Context, networkInfo interface{}) error {
+	return nil
+}
+
+type pvcVolume struct{ name string }
+
+func (v pvcVolume) ID() string        { return v.name }
+func (v pvcVolume) HostPath() string  { return "" }
+func (v pvcVolume) Reference() string { return v.name }
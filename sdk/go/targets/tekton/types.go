@@ -0,0 +1,187 @@
+// Package tekton translates a compiled sykli task graph into Tekton
+// Pipeline/PipelineRun CRDs and provides a Target implementation that
+// can apply them to a cluster (or just print them, in dry-run mode).
+// The conversion itself (Build, Validate) is pure data in, data out -
+// sykli.Pipeline.TektonPipeline does the work of walking the SDK's
+// unexported task fields into the TaskNode shape this package consumes.
+//
+// Only a minimal subset of the real tekton.dev/v1 API types is declared
+// here, just enough to round-trip through YAML and kubectl apply,
+// rather than depending on the full tektoncd/pipeline module for a
+// handful of struct shapes.
+package tekton
+
+// TypeMeta mirrors the Kubernetes TypeMeta every CRD embeds.
+type TypeMeta struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+}
+
+// ObjectMeta mirrors the subset of Kubernetes ObjectMeta this package sets.
+type ObjectMeta struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+// Pipeline is a tekton.dev/v1 Pipeline CRD.
+type Pipeline struct {
+	TypeMeta   `yaml:",inline"`
+	ObjectMeta `yaml:"metadata"`
+	Spec       PipelineSpec `yaml:"spec"`
+}
+
+// PipelineSpec is a Pipeline's spec.
+type PipelineSpec struct {
+	Tasks      []PipelineTask          `yaml:"tasks"`
+	Workspaces []WorkspacePipelineDecl `yaml:"workspaces,omitempty"`
+}
+
+// PipelineTask is one task entry in a Pipeline's task list.
+type PipelineTask struct {
+	Name       string             `yaml:"name"`
+	TaskSpec   *EmbeddedTask      `yaml:"taskSpec,omitempty"`
+	RunAfter   []string           `yaml:"runAfter,omitempty"`
+	Params     []Param            `yaml:"params,omitempty"`
+	Workspaces []WorkspaceBinding `yaml:"workspaces,omitempty"`
+	Matrix     *Matrix            `yaml:"matrix,omitempty"`
+	When       []WhenExpression   `yaml:"when,omitempty"`
+}
+
+// EmbeddedTask is a Tekton taskSpec inlined into a PipelineTask, which is
+// how Build emits tasks (rather than separate, referenced Task objects).
+type EmbeddedTask struct {
+	Params     []ParamSpec         `yaml:"params,omitempty"`
+	Results    []TaskResult        `yaml:"results,omitempty"`
+	Workspaces []WorkspaceTaskDecl `yaml:"workspaces,omitempty"`
+	Steps      []Step              `yaml:"steps"`
+	Sidecars   []Sidecar           `yaml:"sidecars,omitempty"`
+}
+
+// Step is a single container step within a taskSpec. Build always emits
+// exactly one step per sykli task, running its command as a shell script.
+type Step struct {
+	Name       string   `yaml:"name"`
+	Image      string   `yaml:"image"`
+	Script     string   `yaml:"script,omitempty"`
+	WorkingDir string   `yaml:"workingDir,omitempty"`
+	Env        []EnvVar `yaml:"env,omitempty"`
+}
+
+// Sidecar is a container that runs alongside a taskSpec's steps, used here
+// for sykli Service containers.
+type Sidecar struct {
+	Name  string `yaml:"name"`
+	Image string `yaml:"image"`
+}
+
+// EnvVar is a Kubernetes-style name/value environment entry.
+type EnvVar struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// Param is a resolved parameter value passed into a PipelineTask.
+type Param struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// ParamSpec declares a parameter a taskSpec accepts.
+type ParamSpec struct {
+	Name string `yaml:"name"`
+}
+
+// TaskResult declares a named result a taskSpec's step writes to
+// $(results.<name>.path).
+type TaskResult struct {
+	Name string `yaml:"name"`
+}
+
+// WorkspaceTaskDecl declares a workspace a taskSpec expects to be bound.
+type WorkspaceTaskDecl struct {
+	Name string `yaml:"name"`
+}
+
+// WorkspacePipelineDecl declares a workspace a Pipeline expects its
+// PipelineRun to bind.
+type WorkspacePipelineDecl struct {
+	Name string `yaml:"name"`
+}
+
+// WorkspaceBinding binds a workspace name to a volume source, used both on
+// a PipelineTask (binding to the pipeline-level workspace) and on a
+// PipelineRun (binding the pipeline-level workspace to a real volume).
+type WorkspaceBinding struct {
+	Name                  string                `yaml:"name"`
+	EmptyDir              *EmptyDirVolumeSource `yaml:"emptyDir,omitempty"`
+	PersistentVolumeClaim *PVCVolumeSource      `yaml:"persistentVolumeClaim,omitempty"`
+}
+
+// EmptyDirVolumeSource binds a workspace to an ephemeral emptyDir.
+type EmptyDirVolumeSource struct{}
+
+// PVCVolumeSource binds a workspace to an existing PersistentVolumeClaim.
+type PVCVolumeSource struct {
+	ClaimName string `yaml:"claimName"`
+}
+
+// Matrix fans a PipelineTask out over the combinations of its Params.
+type Matrix struct {
+	Params []MatrixParam `yaml:"params,omitempty"`
+}
+
+// MatrixParam is one dimension of a Matrix: Tekton runs the task once per
+// value in Value, combined with every other param's values.
+type MatrixParam struct {
+	Name  string   `yaml:"name"`
+	Value []string `yaml:"value"`
+}
+
+// WhenExpression gates a PipelineTask on a params/results comparison.
+type WhenExpression struct {
+	Input    string   `yaml:"input"`
+	Operator string   `yaml:"operator"`
+	Values   []string `yaml:"values"`
+}
+
+// PipelineRun is a tekton.dev/v1 PipelineRun CRD that executes a Pipeline.
+type PipelineRun struct {
+	TypeMeta   `yaml:",inline"`
+	ObjectMeta `yaml:"metadata"`
+	Spec       PipelineRunSpec `yaml:"spec"`
+}
+
+// PipelineRunSpec is a PipelineRun's spec.
+type PipelineRunSpec struct {
+	PipelineRef  *PipelineRef          `yaml:"pipelineRef,omitempty"`
+	Workspaces   []WorkspaceBinding    `yaml:"workspaces,omitempty"`
+	TaskRunSpecs []PipelineTaskRunSpec `yaml:"taskRunSpecs,omitempty"`
+}
+
+// PipelineRef names the Pipeline a PipelineRun executes.
+type PipelineRef struct {
+	Name string `yaml:"name"`
+}
+
+// PipelineTaskRunSpec carries per-task execution overrides, which is where
+// a sykli Task's K8s(...) options land.
+type PipelineTaskRunSpec struct {
+	PipelineTaskName   string       `yaml:"pipelineTaskName"`
+	ServiceAccountName string       `yaml:"serviceAccountName,omitempty"`
+	PodTemplate        *PodTemplate `yaml:"podTemplate,omitempty"`
+}
+
+// PodTemplate overrides pod-level scheduling for one task's TaskRun.
+type PodTemplate struct {
+	NodeSelector map[string]string `yaml:"nodeSelector,omitempty"`
+	Tolerations  []Toleration      `yaml:"tolerations,omitempty"`
+}
+
+// Toleration mirrors a Kubernetes pod toleration.
+type Toleration struct {
+	Key      string `yaml:"key,omitempty"`
+	Operator string `yaml:"operator,omitempty"`
+	Value    string `yaml:"value,omitempty"`
+	Effect   string `yaml:"effect,omitempty"`
+}
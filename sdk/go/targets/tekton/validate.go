@@ -0,0 +1,91 @@
+package tekton
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TektonUnsupportedError reports a sykli construct that has no Tekton
+// equivalent and could not be lowered to one.
+type TektonUnsupportedError struct {
+	Task   string
+	Reason string
+}
+
+func (e *TektonUnsupportedError) Error() string {
+	return fmt.Sprintf("tekton: task %q: %s", e.Task, e.Reason)
+}
+
+// Validate checks nodes for constructs Build can't represent in Tekton,
+// returning one *TektonUnsupportedError per offending task. Callers that
+// only care about the first problem can check len(errs) > 0 and use
+// errs[0]; Build itself stops at the first one it hits.
+func Validate(nodes []TaskNode) []error {
+	var errs []error
+	for _, n := range nodes {
+		if _, err := lowerWhen(n.When); err != nil {
+			errs = append(errs, &TektonUnsupportedError{Task: n.Name, Reason: err.Error()})
+		}
+	}
+	return errs
+}
+
+// whenClausePattern matches a single branch/tag/event equality check, e.g.
+// branch == 'main' or event != 'pull_request'.
+var whenClausePattern = regexp.MustCompile(`^(branch|tag|event)\s*(==|!=)\s*'([^']*)'$`)
+
+// ciClausePattern matches the boolean ci flag, e.g. ci == true.
+var ciClausePattern = regexp.MustCompile(`^ci\s*(==|!=)\s*(true|false)$`)
+
+// lowerWhen translates a sykli when-condition source into Tekton when
+// expressions. Only a plain, &&-joined list of branch/tag/event/ci
+// equality checks can be lowered - anything else (||, !, matrix.*,
+// output.*, changed()) has no Tekton equivalent and returns an error.
+func lowerWhen(expr string) ([]WhenExpression, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
+	}
+
+	clauses := strings.Split(expr, "&&")
+	out := make([]WhenExpression, 0, len(clauses))
+	for _, clause := range clauses {
+		we, err := lowerClause(strings.TrimSpace(clause))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, we)
+	}
+	return out, nil
+}
+
+func lowerClause(clause string) (WhenExpression, error) {
+	if m := whenClausePattern.FindStringSubmatch(clause); m != nil {
+		ident, op, value := m[1], m[2], m[3]
+		return WhenExpression{
+			Input:    "$(params." + ident + ")",
+			Operator: whenOperator(op),
+			Values:   []string{value},
+		}, nil
+	}
+	if m := ciClausePattern.FindStringSubmatch(clause); m != nil {
+		op, value := m[1], m[2]
+		return WhenExpression{
+			Input:    "$(params.ci)",
+			Operator: whenOperator(op),
+			Values:   []string{value},
+		}, nil
+	}
+	return WhenExpression{}, fmt.Errorf(
+		"when clause %q cannot be lowered to a Tekton \"when\" expression (only branch/tag/event/ci equality checks joined with && are supported)",
+		clause,
+	)
+}
+
+func whenOperator(op string) string {
+	if op == "!=" {
+		return "notin"
+	}
+	return "in"
+}
@@ -0,0 +1,67 @@
+package tekton
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLowerWhenSupportsAndJoinedEqualityChecks(t *testing.T) {
+	when, err := lowerWhen("branch == 'main' && ci == true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(when) != 2 {
+		t.Fatalf("expected 2 when expressions, got %d: %+v", len(when), when)
+	}
+	if when[0].Input != "$(params.branch)" || when[0].Operator != "in" || when[0].Values[0] != "main" {
+		t.Errorf("unexpected first when expression: %+v", when[0])
+	}
+	if when[1].Input != "$(params.ci)" || when[1].Operator != "in" || when[1].Values[0] != "true" {
+		t.Errorf("unexpected second when expression: %+v", when[1])
+	}
+}
+
+func TestLowerWhenNotEqualBecomesNotIn(t *testing.T) {
+	when, err := lowerWhen("event != 'pull_request'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if when[0].Operator != "notin" {
+		t.Errorf("expected notin operator, got %q", when[0].Operator)
+	}
+}
+
+func TestLowerWhenEmptyConditionIsUnconditional(t *testing.T) {
+	when, err := lowerWhen("")
+	if err != nil || when != nil {
+		t.Errorf("expected no when expressions for an empty condition, got %+v, %v", when, err)
+	}
+}
+
+func TestLowerWhenRejectsUnrepresentableConditions(t *testing.T) {
+	cases := []string{
+		"changed('src/**')",
+		"output.foo == 'bar'",
+		"matrix.os == 'linux'",
+		"branch == 'main' || branch == 'develop'",
+	}
+	for _, expr := range cases {
+		if _, err := lowerWhen(expr); err == nil {
+			t.Errorf("expected %q to be rejected as unsupported", expr)
+		}
+	}
+}
+
+func TestValidateWrapsEachFailureInTektonUnsupportedError(t *testing.T) {
+	errs := Validate([]TaskNode{{Name: "deploy", When: "changed('src/**')"}})
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(errs))
+	}
+	unsupported, ok := errs[0].(*TektonUnsupportedError)
+	if !ok {
+		t.Fatalf("expected *TektonUnsupportedError, got %T", errs[0])
+	}
+	if unsupported.Task != "deploy" || !strings.Contains(unsupported.Error(), "deploy") {
+		t.Errorf("unexpected error: %v", unsupported)
+	}
+}
@@ -0,0 +1,101 @@
+package sykli
+
+import (
+	"fmt"
+
+	"sykli.dev/go/targets/tekton"
+)
+
+// =============================================================================
+// TEKTON PIPELINE EXPORT
+// =============================================================================
+
+// TektonPipeline translates p's task graph into a Tekton Pipeline +
+// PipelineRun manifest pair (see the targets/tekton package for the
+// Pipeline/PipelineRun shapes and BuildOptions). Each sykli Task becomes
+// an inline Tekton taskSpec with runAfter derived from DependsOn, Output
+// becomes a Tekton result, InputFrom becomes a param wired through
+// $(tasks.<name>.results.<x>), Service containers become sidecars, the
+// first directory or cache mount becomes a workspace (later ones are not
+// carried over), Matrix becomes the
+// PipelineTask's matrix field, and K8s(...) maps onto
+// taskRunSpecs[].podTemplate on the PipelineRun.
+//
+// tekton.Validate runs first, so an unsupported construct - a
+// MatrixExclude/MatrixInclude, which Tekton's matrix field can't
+// express, or a When condition that isn't a plain &&-joined
+// branch/tag/event/ci equality check - fails with a
+// *tekton.TektonUnsupportedError before any YAML is produced.
+func (p *Pipeline) TektonPipeline(opts tekton.BuildOptions) (*tekton.Pipeline, *tekton.PipelineRun, error) {
+	if cycle := p.detectCycle(); cycle != nil {
+		return nil, nil, fmt.Errorf("TektonPipeline: dependency cycle detected: %s", formatCyclePath(cycle))
+	}
+
+	nodes := make([]tekton.TaskNode, len(p.tasks))
+	for i, t := range p.tasks {
+		if len(t.matrixExclude) > 0 || len(t.matrixInclude) > 0 {
+			return nil, nil, &tekton.TektonUnsupportedError{
+				Task:   t.name,
+				Reason: "MatrixExclude/MatrixInclude have no equivalent in Tekton's matrix field",
+			}
+		}
+		nodes[i] = tektonTaskNode(t)
+	}
+
+	if errs := tekton.Validate(nodes); len(errs) > 0 {
+		return nil, nil, errs[0]
+	}
+
+	return tekton.Build(nodes, opts)
+}
+
+// tektonTaskNode converts t into the plain-data shape tekton.Build
+// expects, resolving its unexported state (mounts, task inputs, services,
+// K8s options) into the subpackage's types.
+func tektonTaskNode(t *Task) tekton.TaskNode {
+	node := tekton.TaskNode{
+		Name:      t.name,
+		Command:   t.command,
+		Image:     t.container,
+		Workdir:   t.workdir,
+		Env:       t.env,
+		DependsOn: t.dependsOn,
+		Outputs:   t.outputs,
+		When:      t.when,
+		Matrix:    t.matrix,
+	}
+
+	for _, ti := range t.taskInputs {
+		node.TaskInputs = append(node.TaskInputs, tekton.TaskInputRef{
+			FromTask:   ti.fromTask,
+			OutputName: ti.outputName,
+			DestPath:   ti.destPath,
+		})
+	}
+
+	for _, svc := range t.services {
+		node.Services = append(node.Services, tekton.ServiceRef{Name: svc.name, Image: svc.image})
+	}
+
+	for _, m := range t.mounts {
+		if m.mountType != "directory" && m.mountType != "cache" {
+			continue
+		}
+		node.Workspace = &tekton.WorkspaceRef{Name: m.resource, Path: m.path, Kind: m.mountType}
+		break
+	}
+
+	if t.k8sOptions != nil {
+		node.K8s = &tekton.K8sOptions{
+			NodeSelector:   t.k8sOptions.NodeSelector,
+			ServiceAccount: t.k8sOptions.ServiceAccount,
+		}
+		for _, tol := range t.k8sOptions.Tolerations {
+			node.K8s.Tolerations = append(node.K8s.Tolerations, tekton.K8sToleration{
+				Key: tol.Key, Operator: tol.Operator, Value: tol.Value, Effect: tol.Effect,
+			})
+		}
+	}
+
+	return node
+}
@@ -0,0 +1,132 @@
+package sykli
+
+import "fmt"
+
+// =============================================================================
+// TEMPLATE COMPOSITION
+// =============================================================================
+
+// Extends marks this template as inheriting from one or more parent
+// templates. Parents are resolved and applied, in order, before this
+// template's own settings whenever the template is used via Task.From -
+// mirroring the precedence rule From already uses between a template and
+// the task applying it: later beats earlier, and the most specific level
+// wins last.
+func (t *Template) Extends(parents ...*Template) *Template {
+	for _, parent := range parents {
+		if parent == nil {
+			log.Panic().Str("template", t.name).Msg("Extends: parent template cannot be nil")
+		}
+	}
+	t.extends = append(t.extends, parents...)
+	return t
+}
+
+// mergeTemplateChain folds a resolved template chain into one synthetic
+// template, later entries overriding earlier ones field-by-field (mounts
+// are concatenated in chain order instead, since mounts don't have a single
+// slot to override). From applies the result in a single pass so a
+// later-but-already-set field can still beat an earlier template - applying
+// each template in the chain directly onto the task would let the first
+// template's "don't overwrite" guard block every template after it.
+func mergeTemplateChain(chain []*Template) *Template {
+	merged := &Template{env: make(map[string]string)}
+	for _, tmpl := range chain {
+		if tmpl.container != "" {
+			merged.container = tmpl.container
+		}
+		if tmpl.workdir != "" {
+			merged.workdir = tmpl.workdir
+		}
+		for k, v := range tmpl.env {
+			merged.env[k] = v
+		}
+		merged.mounts = append(merged.mounts, tmpl.mounts...)
+	}
+	return merged
+}
+
+// applyTemplate merges a single template's settings onto a task. Template
+// settings are applied first, then task-specific settings override them -
+// the same rule From has always documented for a single template.
+func applyTemplate(t *Task, tmpl *Template) {
+	if tmpl.container != "" && t.container == "" {
+		t.container = tmpl.container
+	}
+	if tmpl.workdir != "" && t.workdir == "" {
+		t.workdir = tmpl.workdir
+	}
+
+	for k, v := range tmpl.env {
+		if _, exists := t.env[k]; !exists {
+			t.env[k] = v
+		}
+	}
+
+	if len(tmpl.mounts) > 0 {
+		t.mounts = append(tmpl.mounts, t.mounts...)
+	}
+}
+
+// resolveTemplateChain expands tmpls and their transitive Extends() parents
+// into a single flat merge order: ancestors before descendants, and where a
+// template is reachable more than once (e.g. two overlays sharing a common
+// base, or the same template passed to From twice), only its last position
+// is kept so precedence stays unambiguous. Cycles in the Extends graph are
+// rejected by a depth-first walk that checks each template against the
+// current Extends() path.
+func resolveTemplateChain(tmpls []*Template) ([]*Template, error) {
+	var raw []*Template
+	var path []*Template
+
+	var visit func(t *Template) error
+	visit = func(t *Template) error {
+		for _, ancestor := range path {
+			if ancestor == t {
+				return fmt.Errorf("template cycle detected: %s", formatTemplateCyclePath(path, t))
+			}
+		}
+		path = append(path, t)
+		for _, parent := range t.extends {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		raw = append(raw, t)
+		return nil
+	}
+
+	for _, t := range tmpls {
+		if err := visit(t); err != nil {
+			return nil, err
+		}
+	}
+
+	lastIndex := make(map[*Template]int, len(raw))
+	for i, t := range raw {
+		lastIndex[t] = i
+	}
+	chain := make([]*Template, 0, len(lastIndex))
+	for i, t := range raw {
+		if lastIndex[t] == i {
+			chain = append(chain, t)
+		}
+	}
+	return chain, nil
+}
+
+// formatTemplateCyclePath formats the Extends() path that led back to a
+// template already on the stack, e.g. "a -> b -> a".
+func formatTemplateCyclePath(path []*Template, closing *Template) string {
+	names := make([]string, 0, len(path)+1)
+	for _, t := range path {
+		names = append(names, t.name)
+	}
+	names = append(names, closing.name)
+	result := names[0]
+	for _, n := range names[1:] {
+		result += " -> " + n
+	}
+	return result
+}
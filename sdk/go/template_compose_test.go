@@ -0,0 +1,126 @@
+package sykli
+
+import "testing"
+
+func TestExtendsInheritsFromParentTemplate(t *testing.T) {
+	p := New()
+	base := p.Template("base").Container("golang:1.21").Env("FOO", "base")
+	overlay := p.Template("overlay").Extends(base).Env("BAR", "overlay")
+
+	p.Task("test").From(overlay).Run("go test ./...")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task := getTaskMap(result, "test")
+	if task["container"] != "golang:1.21" {
+		t.Errorf("expected inherited container golang:1.21, got %v", task["container"])
+	}
+	env := task["env"].(map[string]interface{})
+	if env["FOO"] != "base" || env["BAR"] != "overlay" {
+		t.Errorf("expected FOO=base, BAR=overlay, got %v", env)
+	}
+}
+
+func TestExtendsLaterOverridesEarlier(t *testing.T) {
+	p := New()
+	base := p.Template("base").Container("golang:1.20").Env("FOO", "base")
+	overlay := p.Template("overlay").Extends(base).Container("golang:1.21").Env("FOO", "overlay")
+
+	p.Task("test").From(overlay).Run("go test ./...")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task := getTaskMap(result, "test")
+	if task["container"] != "golang:1.21" {
+		t.Errorf("expected overlay's container to win, got %v", task["container"])
+	}
+	env := task["env"].(map[string]interface{})
+	if env["FOO"] != "overlay" {
+		t.Errorf("expected overlay's FOO to win, got %v", env["FOO"])
+	}
+}
+
+func TestFromMultipleTemplatesMergeInDeclaredOrder(t *testing.T) {
+	p := New()
+	lang := p.Template("lang").Container("golang:1.21").Env("FOO", "lang")
+	ci := p.Template("ci").Env("FOO", "ci").Env("CI", "true")
+
+	p.Task("test").From(lang, ci).Run("go test ./...")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task := getTaskMap(result, "test")
+	if task["container"] != "golang:1.21" {
+		t.Errorf("expected container from lang, got %v", task["container"])
+	}
+	env := task["env"].(map[string]interface{})
+	if env["FOO"] != "ci" {
+		t.Errorf("expected the later template (ci) to win on shared key FOO, got %v", env["FOO"])
+	}
+	if env["CI"] != "true" {
+		t.Errorf("expected CI=true from ci template, got %v", env)
+	}
+}
+
+func TestFromTaskLevelAlwaysWinsOverTemplateChain(t *testing.T) {
+	p := New()
+	base := p.Template("base").Env("FOO", "base")
+	overlay := p.Template("overlay").Extends(base).Env("FOO", "overlay")
+
+	p.Task("test").From(overlay).Env("FOO", "task").Run("echo $FOO")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task := getTaskMap(result, "test")
+	env := task["env"].(map[string]interface{})
+	if env["FOO"] != "task" {
+		t.Errorf("expected task-level FOO to win, got %v", env["FOO"])
+	}
+}
+
+func TestExtendsCycleDetected(t *testing.T) {
+	p := New()
+	a := p.Template("a")
+	b := p.Template("b")
+	a.Extends(b)
+	b.Extends(a)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for a template Extends() cycle")
+		}
+	}()
+	p.Task("test").From(a).Run("echo test")
+}
+
+func TestExtendsNilParentPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for nil parent passed to Extends")
+		}
+	}()
+	p := New()
+	p.Template("base").Extends(nil)
+}
+
+func TestFromNoTemplatesPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic calling From with no templates")
+		}
+	}()
+	p := New()
+	p.Task("test").From()
+}
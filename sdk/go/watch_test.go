@@ -0,0 +1,80 @@
+package sykli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSrcAndDebounceEmitted(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...").Src("**/*.go").Debounce(3000)
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := findTaskByName(result, "build")
+	watch := task["watch"].(map[string]interface{})
+	src := watch["src"].([]interface{})
+	if len(src) != 1 || src[0] != "**/*.go" {
+		t.Errorf("expected src=[**/*.go], got %v", src)
+	}
+	if watch["debounce_ms"] != float64(3000) {
+		t.Errorf("expected debounce_ms=3000, got %v", watch["debounce_ms"])
+	}
+}
+
+func TestSrcUnionsAcrossCalls(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...").Src("**/*.go").Src("**/*.mod")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := findTaskByName(result, "build")["watch"].(map[string]interface{})["src"].([]interface{})
+	if len(src) != 2 {
+		t.Errorf("expected both globs to union, got %v", src)
+	}
+}
+
+func TestWatchOmittedWhenSrcUnset(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...").Debounce(500)
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := findTaskByName(result, "build")["watch"]; ok {
+		t.Error("expected no watch field when Src was never called")
+	}
+}
+
+func TestDebounceNegativePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for negative debounce")
+		}
+	}()
+	p := New()
+	p.Task("build").Run("go build ./...").Debounce(-1)
+}
+
+func TestWatchRoundTripsThroughParse(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...").Src("**/*.go").Debounce(1500)
+
+	var buf bytes.Buffer
+	if err := p.EmitTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(&buf, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := parsed.tasks[0]
+	if len(task.watchSrc) != 1 || task.watchSrc[0] != "**/*.go" || task.watchDebounce != 1500 {
+		t.Errorf("unexpected parsed watch config: src=%v debounce=%d", task.watchSrc, task.watchDebounce)
+	}
+}
@@ -0,0 +1,623 @@
+package sykli
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// WHEN CONDITIONS: LEXER / PARSER / AST / EVALUATOR
+// =============================================================================
+
+// WhenNode is an AST node produced by parsing a When(...) condition.
+// Concrete types are BinaryExpr, UnaryExpr, IdentExpr, LiteralExpr, and
+// CallExpr.
+type WhenNode interface {
+	isWhenNode()
+	// toJSON returns this node's canonical, runner-consumable JSON form.
+	toJSON() interface{}
+}
+
+// BinaryExpr is `Left Op Right`, where Op is one of "&&", "||", "==", "!=",
+// "in", or "matches".
+type BinaryExpr struct {
+	Op    string
+	Left  WhenNode
+	Right WhenNode
+}
+
+// UnaryExpr is `Op Operand`, where Op is "!".
+type UnaryExpr struct {
+	Op      string
+	Operand WhenNode
+}
+
+// IdentExpr references a context value: "branch", "tag", "event", "ci",
+// "env.NAME", "matrix.KEY", or "output.KEY".
+type IdentExpr struct {
+	Name string
+}
+
+// LiteralExpr is a quoted string constant.
+type LiteralExpr struct {
+	Value string
+}
+
+// CallExpr is `Name(Args...)`. Currently only changed(<glob>) is supported.
+type CallExpr struct {
+	Name string
+	Args []WhenNode
+}
+
+func (*BinaryExpr) isWhenNode()  {}
+func (*UnaryExpr) isWhenNode()   {}
+func (*IdentExpr) isWhenNode()   {}
+func (*LiteralExpr) isWhenNode() {}
+func (*CallExpr) isWhenNode()    {}
+
+func (n *BinaryExpr) toJSON() interface{} {
+	return map[string]interface{}{"op": n.Op, "left": n.Left.toJSON(), "right": n.Right.toJSON()}
+}
+
+func (n *UnaryExpr) toJSON() interface{} {
+	return map[string]interface{}{"op": n.Op, "operand": n.Operand.toJSON()}
+}
+
+func (n *IdentExpr) toJSON() interface{} {
+	return map[string]interface{}{"ident": n.Name}
+}
+
+func (n *LiteralExpr) toJSON() interface{} {
+	return map[string]interface{}{"literal": n.Value}
+}
+
+func (n *CallExpr) toJSON() interface{} {
+	args := make([]interface{}, len(n.Args))
+	for i, a := range n.Args {
+		args[i] = a.toJSON()
+	}
+	return map[string]interface{}{"call": n.Name, "args": args}
+}
+
+// WhenContext supplies the values When conditions are evaluated against.
+// Matrix holds the current matrix combination's values (see ExpandMatrix),
+// addressable as "matrix.KEY"; Outputs holds upstream tasks' output values,
+// addressable as "output.KEY"; ChangedFiles feeds the changed(<glob>)
+// predicate and is typically produced by GitChangedFiles.
+type WhenContext struct {
+	Branch       string
+	Tag          string
+	Event        string
+	IsCI         bool
+	Env          map[string]string
+	Matrix       map[string]string
+	Outputs      map[string]string
+	ChangedFiles []string
+}
+
+// GitChangedFiles returns paths that differ between base and the working
+// tree, including uncommitted changes, suitable for WhenContext.ChangedFiles.
+// base defaults to "HEAD" when empty.
+func GitChangedFiles(base string) ([]string, error) {
+	if base == "" {
+		base = "HEAD"
+	}
+	out, err := exec.Command("git", "diff", "--name-only", base).Output()
+	if err != nil {
+		return nil, fmt.Errorf("when: git diff --name-only %s: %w", base, err)
+	}
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// WhenExpr wraps a parsed (or directly constructed) When AST together with
+// its canonical source form, so it can be handed to Task.WhenExpr or
+// combined with And/Or.
+type WhenExpr struct {
+	source string
+	ast    WhenNode
+}
+
+// String returns the condition's canonical source form.
+func (w *WhenExpr) String() string {
+	return w.source
+}
+
+// And combines two conditions with &&.
+func (w *WhenExpr) And(other *WhenExpr) *WhenExpr {
+	return &WhenExpr{
+		source: fmt.Sprintf("(%s) && (%s)", w.source, other.source),
+		ast:    &BinaryExpr{Op: "&&", Left: w.ast, Right: other.ast},
+	}
+}
+
+// Or combines two conditions with ||.
+func (w *WhenExpr) Or(other *WhenExpr) *WhenExpr {
+	return &WhenExpr{
+		source: fmt.Sprintf("(%s) || (%s)", w.source, other.source),
+		ast:    &BinaryExpr{Op: "||", Left: w.ast, Right: other.ast},
+	}
+}
+
+// Not negates a condition.
+func (w *WhenExpr) Not() *WhenExpr {
+	return &WhenExpr{
+		source: fmt.Sprintf("!(%s)", w.source),
+		ast:    &UnaryExpr{Op: "!", Operand: w.ast},
+	}
+}
+
+// Evaluate runs the condition against ctx.
+func (w *WhenExpr) Evaluate(ctx WhenContext) (bool, error) {
+	return evalWhenBool(w.ast, ctx)
+}
+
+// OnBranch builds a condition matching when WhenContext.Branch equals name.
+func OnBranch(name string) *WhenExpr {
+	return &WhenExpr{
+		source: fmt.Sprintf("branch == %q", name),
+		ast:    &BinaryExpr{Op: "==", Left: &IdentExpr{Name: "branch"}, Right: &LiteralExpr{Value: name}},
+	}
+}
+
+// OnTag builds a condition matching any tag push (WhenContext.Tag non-empty).
+func OnTag() *WhenExpr {
+	return &WhenExpr{
+		source: `tag != ""`,
+		ast:    &BinaryExpr{Op: "!=", Left: &IdentExpr{Name: "tag"}, Right: &LiteralExpr{Value: ""}},
+	}
+}
+
+// OnEvent builds a condition matching when WhenContext.Event equals name
+// (e.g. "push", "pull_request").
+func OnEvent(name string) *WhenExpr {
+	return &WhenExpr{
+		source: fmt.Sprintf("event == %q", name),
+		ast:    &BinaryExpr{Op: "==", Left: &IdentExpr{Name: "event"}, Right: &LiteralExpr{Value: name}},
+	}
+}
+
+// WhenEval parses and evaluates cond against ctx in one call, useful for
+// unit-testing When conditions without building a Pipeline.
+func WhenEval(cond string, ctx WhenContext) (bool, error) {
+	ast, err := parseWhen(cond)
+	if err != nil {
+		return false, err
+	}
+	return evalWhenBool(ast, ctx)
+}
+
+// =============================================================================
+// LEXER
+// =============================================================================
+
+type whenTokenKind int
+
+const (
+	tokEOF whenTokenKind = iota
+	tokIdent
+	tokString
+	tokEq
+	tokNeq
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+	tokComma
+	tokIn
+	tokMatches
+)
+
+type whenToken struct {
+	kind whenTokenKind
+	text string
+}
+
+func lexWhen(input string) ([]whenToken, error) {
+	var tokens []whenToken
+	runes := []rune(input)
+	i := 0
+	for i < len(runes) {
+		c := runes[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, whenToken{tokLParen, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, whenToken{tokRParen, ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, whenToken{tokComma, ","})
+			i++
+		case c == '!' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, whenToken{tokNeq, "!="})
+			i += 2
+		case c == '!':
+			tokens = append(tokens, whenToken{tokNot, "!"})
+			i++
+		case c == '=' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, whenToken{tokEq, "=="})
+			i += 2
+		case c == '&' && i+1 < len(runes) && runes[i+1] == '&':
+			tokens = append(tokens, whenToken{tokAnd, "&&"})
+			i += 2
+		case c == '|' && i+1 < len(runes) && runes[i+1] == '|':
+			tokens = append(tokens, whenToken{tokOr, "||"})
+			i += 2
+		case c == '"' || c == '\'':
+			quote := c
+			j := i + 1
+			var sb strings.Builder
+			for j < len(runes) && runes[j] != quote {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				sb.WriteRune(runes[j])
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("when: unterminated string literal")
+			}
+			tokens = append(tokens, whenToken{tokString, sb.String()})
+			i = j + 1
+		case isWhenIdentRune(c):
+			j := i
+			for j < len(runes) && isWhenIdentRune(runes[j]) {
+				j++
+			}
+			word := string(runes[i:j])
+			switch word {
+			case "in":
+				tokens = append(tokens, whenToken{tokIn, word})
+			case "matches":
+				tokens = append(tokens, whenToken{tokMatches, word})
+			default:
+				tokens = append(tokens, whenToken{tokIdent, word})
+			}
+			i = j
+		default:
+			return nil, fmt.Errorf("when: unexpected character %q", c)
+		}
+	}
+	tokens = append(tokens, whenToken{tokEOF, ""})
+	return tokens, nil
+}
+
+func isWhenIdentRune(c rune) bool {
+	return c == '_' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// =============================================================================
+// PARSER (recursive descent, ascending precedence: || , && , ! , comparison , primary)
+// =============================================================================
+
+type whenParser struct {
+	tokens []whenToken
+	pos    int
+}
+
+func parseWhen(input string) (WhenNode, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, fmt.Errorf("when: condition cannot be empty")
+	}
+	tokens, err := lexWhen(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &whenParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("when: unexpected token %q after expression", p.peek().text)
+	}
+	return node, nil
+}
+
+func (p *whenParser) peek() whenToken {
+	return p.tokens[p.pos]
+}
+
+func (p *whenParser) next() whenToken {
+	t := p.tokens[p.pos]
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+	return t
+}
+
+func (p *whenParser) parseOr() (WhenNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: "||", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *whenParser) parseAnd() (WhenNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: "&&", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *whenParser) parseUnary() (WhenNode, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryExpr{Op: "!", Operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *whenParser) parseComparison() (WhenNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	switch p.peek().kind {
+	case tokEq, tokNeq, tokIn, tokMatches:
+		op := p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		opText := map[whenTokenKind]string{tokEq: "==", tokNeq: "!=", tokIn: "in", tokMatches: "matches"}[op.kind]
+		return &BinaryExpr{Op: opText, Left: left, Right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *whenParser) parsePrimary() (WhenNode, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokLParen:
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, fmt.Errorf("when: expected ')'")
+		}
+		p.next()
+		return node, nil
+	case tokIdent:
+		p.next()
+		if p.peek().kind == tokLParen {
+			return p.parseCall(tok.text)
+		}
+		return &IdentExpr{Name: tok.text}, nil
+	case tokString:
+		p.next()
+		return &LiteralExpr{Value: tok.text}, nil
+	default:
+		return nil, fmt.Errorf("when: unexpected token %q", tok.text)
+	}
+}
+
+// parseCall parses a `name(arg, ...)` call. The caller has already consumed
+// name itself; p.peek() is the opening "(".
+func (p *whenParser) parseCall(name string) (WhenNode, error) {
+	p.next() // consume "("
+	var args []WhenNode
+	if p.peek().kind != tokRParen {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.peek().kind != tokRParen {
+		return nil, fmt.Errorf("when: expected ')' to close %s(...)", name)
+	}
+	p.next()
+	return &CallExpr{Name: name, Args: args}, nil
+}
+
+// =============================================================================
+// EVALUATOR
+// =============================================================================
+
+func evalWhenBool(node WhenNode, ctx WhenContext) (bool, error) {
+	switch n := node.(type) {
+	case *BinaryExpr:
+		switch n.Op {
+		case "&&":
+			l, err := evalWhenBool(n.Left, ctx)
+			if err != nil {
+				return false, err
+			}
+			r, err := evalWhenBool(n.Right, ctx)
+			if err != nil {
+				return false, err
+			}
+			return l && r, nil
+		case "||":
+			l, err := evalWhenBool(n.Left, ctx)
+			if err != nil {
+				return false, err
+			}
+			r, err := evalWhenBool(n.Right, ctx)
+			if err != nil {
+				return false, err
+			}
+			return l || r, nil
+		case "==", "!=":
+			l, err := evalWhenValue(n.Left, ctx)
+			if err != nil {
+				return false, err
+			}
+			r, err := evalWhenValue(n.Right, ctx)
+			if err != nil {
+				return false, err
+			}
+			if n.Op == "==" {
+				return l == r, nil
+			}
+			return l != r, nil
+		case "in":
+			l, err := evalWhenValue(n.Left, ctx)
+			if err != nil {
+				return false, err
+			}
+			r, err := evalWhenValue(n.Right, ctx)
+			if err != nil {
+				return false, err
+			}
+			for _, option := range strings.Split(r, ",") {
+				if strings.TrimSpace(option) == l {
+					return true, nil
+				}
+			}
+			return false, nil
+		case "matches":
+			l, err := evalWhenValue(n.Left, ctx)
+			if err != nil {
+				return false, err
+			}
+			r, err := evalWhenValue(n.Right, ctx)
+			if err != nil {
+				return false, err
+			}
+			return regexp.MatchString(r, l)
+		default:
+			return false, fmt.Errorf("when: unknown operator %q", n.Op)
+		}
+	case *UnaryExpr:
+		operand, err := evalWhenBool(n.Operand, ctx)
+		if err != nil {
+			return false, err
+		}
+		return !operand, nil
+	case *IdentExpr:
+		if n.Name == "ci" {
+			return ctx.IsCI, nil
+		}
+		return false, fmt.Errorf("when: identifier %q cannot be used as a condition on its own", n.Name)
+	case *CallExpr:
+		switch n.Name {
+		case "changed":
+			if len(n.Args) != 1 {
+				return false, fmt.Errorf("when: changed() takes exactly one glob argument")
+			}
+			glob, err := evalWhenValue(n.Args[0], ctx)
+			if err != nil {
+				return false, err
+			}
+			return matchesAnyChangedFile(glob, ctx.ChangedFiles), nil
+		default:
+			return false, fmt.Errorf("when: unknown function %q", n.Name)
+		}
+	default:
+		return false, fmt.Errorf("when: expression does not evaluate to a boolean")
+	}
+}
+
+// matchesAnyChangedFile reports whether any file matches glob, where "**"
+// matches across path separators and "*" matches within one path segment.
+func matchesAnyChangedFile(glob string, files []string) bool {
+	re, err := regexp.Compile(globToRegexp(glob))
+	if err != nil {
+		return false
+	}
+	for _, f := range files {
+		if re.MatchString(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp translates a glob pattern into an anchored regexp, the same
+// way changed(<glob>) is documented to match paths.
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); {
+		switch {
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i += 2
+		case glob[i] == '*':
+			sb.WriteString("[^/]*")
+			i++
+		case glob[i] == '?':
+			sb.WriteString(".")
+			i++
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+			i++
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
+
+func evalWhenValue(node WhenNode, ctx WhenContext) (string, error) {
+	switch n := node.(type) {
+	case *IdentExpr:
+		switch {
+		case n.Name == "branch":
+			return ctx.Branch, nil
+		case n.Name == "tag":
+			return ctx.Tag, nil
+		case n.Name == "event":
+			return ctx.Event, nil
+		case n.Name == "ci":
+			return strconv.FormatBool(ctx.IsCI), nil
+		case n.Name == "true" || n.Name == "false":
+			return n.Name, nil
+		case strings.HasPrefix(n.Name, "env."):
+			return ctx.Env[strings.TrimPrefix(n.Name, "env.")], nil
+		case strings.HasPrefix(n.Name, "matrix."):
+			return ctx.Matrix[strings.TrimPrefix(n.Name, "matrix.")], nil
+		case strings.HasPrefix(n.Name, "output."):
+			return ctx.Outputs[strings.TrimPrefix(n.Name, "output.")], nil
+		default:
+			return "", fmt.Errorf("when: unknown identifier %q", n.Name)
+		}
+	case *LiteralExpr:
+		return n.Value, nil
+	default:
+		return "", fmt.Errorf("when: expression cannot be used as a value")
+	}
+}
@@ -0,0 +1,186 @@
+package sykli
+
+import "testing"
+
+func TestWhenEvalEquality(t *testing.T) {
+	ok, err := WhenEval("branch == 'main'", WhenContext{Branch: "main"})
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+
+	ok, err = WhenEval("branch == 'main'", WhenContext{Branch: "dev"})
+	if err != nil || ok {
+		t.Fatalf("expected false, got %v, %v", ok, err)
+	}
+}
+
+func TestWhenEvalNotEqualEmptyTag(t *testing.T) {
+	ok, err := WhenEval(`tag != ""`, WhenContext{Tag: "v1.0.0"})
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+}
+
+func TestWhenEvalAndOr(t *testing.T) {
+	ctx := WhenContext{Branch: "main", Event: "push"}
+	ok, err := WhenEval("branch == 'main' && event == 'push'", ctx)
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+
+	ok, err = WhenEval("branch == 'dev' || event == 'push'", ctx)
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+}
+
+func TestWhenEvalNot(t *testing.T) {
+	ok, err := WhenEval("!(branch == 'main')", WhenContext{Branch: "dev"})
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+}
+
+func TestWhenEvalCI(t *testing.T) {
+	ok, err := WhenEval("ci == true", WhenContext{IsCI: true})
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+}
+
+func TestWhenEvalEnv(t *testing.T) {
+	ok, err := WhenEval("env.DEPLOY == 'yes'", WhenContext{Env: map[string]string{"DEPLOY": "yes"}})
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+}
+
+func TestWhenEvalIn(t *testing.T) {
+	ok, err := WhenEval("branch in 'main,develop'", WhenContext{Branch: "develop"})
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+}
+
+func TestWhenEvalMatches(t *testing.T) {
+	ok, err := WhenEval(`branch matches "^release/.*"`, WhenContext{Branch: "release/1.0"})
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+}
+
+func TestWhenEvalInvalidSyntaxErrors(t *testing.T) {
+	if _, err := WhenEval("branch == ", WhenContext{}); err == nil {
+		t.Error("expected parse error for incomplete expression")
+	}
+}
+
+func TestTaskWhenPanicsOnInvalidCondition(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for invalid When condition")
+		}
+	}()
+	p := New()
+	p.Task("deploy").Run("./deploy.sh").When("branch ==")
+}
+
+func TestTaskWhenEmitsSourceAndAST(t *testing.T) {
+	p := New()
+	p.Task("deploy").Run("./deploy.sh").When("branch == 'main'")
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := findTaskByName(result, "deploy")
+	if task["when"] != "branch == 'main'" {
+		t.Errorf("expected when source preserved, got %v", task["when"])
+	}
+	ast := task["when_ast"].(map[string]interface{})
+	if ast["op"] != "==" {
+		t.Errorf("expected canonical AST with op==, got %+v", ast)
+	}
+}
+
+func TestOnBranchOnEventCombinators(t *testing.T) {
+	expr := OnEvent("push").And(OnBranch("main"))
+	ok, err := expr.Evaluate(WhenContext{Branch: "main", Event: "push"})
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+
+	ok, err = expr.Evaluate(WhenContext{Branch: "dev", Event: "push"})
+	if err != nil || ok {
+		t.Fatalf("expected false, got %v, %v", ok, err)
+	}
+}
+
+func TestOnTagCombinator(t *testing.T) {
+	ok, err := OnTag().Evaluate(WhenContext{Tag: "v1.0.0"})
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+	ok, err = OnTag().Evaluate(WhenContext{})
+	if err != nil || ok {
+		t.Fatalf("expected false, got %v, %v", ok, err)
+	}
+}
+
+func TestWhenEvalMatrix(t *testing.T) {
+	ok, err := WhenEval("matrix.os == 'linux'", WhenContext{Matrix: map[string]string{"os": "linux"}})
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+}
+
+func TestWhenEvalOutput(t *testing.T) {
+	ok, err := WhenEval("output.version != ''", WhenContext{Outputs: map[string]string{"version": "1.2.3"}})
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+}
+
+func TestWhenEvalChanged(t *testing.T) {
+	ctx := WhenContext{ChangedFiles: []string{"sdk/go/when.go", "README.md"}}
+
+	ok, err := WhenEval(`changed("sdk/go/*.go")`, ctx)
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+
+	ok, err = WhenEval(`changed("docs/**")`, ctx)
+	if err != nil || ok {
+		t.Fatalf("expected false, got %v, %v", ok, err)
+	}
+}
+
+func TestWhenEvalChangedDoubleStarCrossesDirectories(t *testing.T) {
+	ctx := WhenContext{ChangedFiles: []string{"sdk/go/secrets/vault.go"}}
+
+	ok, err := WhenEval(`changed("sdk/**/*.go")`, ctx)
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+}
+
+func TestWhenEvalChangedWrongArgCountErrors(t *testing.T) {
+	if _, err := WhenEval(`changed()`, WhenContext{}); err == nil {
+		t.Error("expected error for changed() with no arguments")
+	}
+}
+
+func TestTaskWhenExprEmitsCombinedSource(t *testing.T) {
+	p := New()
+	p.Task("deploy").Run("./deploy.sh").WhenExpr(OnBranch("main").And(OnEvent("push")))
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := findTaskByName(result, "deploy")
+	ast := task["when_ast"].(map[string]interface{})
+	if ast["op"] != "&&" {
+		t.Errorf("expected combined AST with op==&&, got %+v", ast)
+	}
+}
@@ -0,0 +1,204 @@
+package sykli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// =============================================================================
+// YAML FRONT END
+// =============================================================================
+
+// LoadYAML reads a human-authored YAML pipeline file and builds a
+// *Pipeline from it, driving the same fluent API a Go caller would (see
+// applyExternalPipeline) so it gets the same validation, cycle detection,
+// and EmitTo output as every other front end. The YAML uses the same
+// field names as externalPipeline's json tags (name, command, container,
+// depends_on, ...) - there's a single schema, just two surface syntaxes -
+// so it never drifts from what LoadJsonnet/LoadCUE/Include already accept.
+//
+// Two YAML-only conveniences are supported on top of that shared schema:
+// ordinary anchors/aliases (&foo / *foo), which go-yaml resolves before
+// LoadYAML ever sees the tree, and a custom "!include relative/path.yaml"
+// tag that splices another file's node in place, so a large pipeline can
+// be split across files (e.g. a shared "tasks: !include common-tasks.yaml").
+func LoadYAML(path string) (*Pipeline, error) {
+	var root yaml.Node
+	if err := decodeYAMLFile(path, &root); err != nil {
+		return nil, fmt.Errorf("LoadYAML: %w", err)
+	}
+	if err := resolveIncludes(&root, filepath.Dir(path)); err != nil {
+		return nil, fmt.Errorf("LoadYAML: %w", err)
+	}
+	if err := validateExternalYAML(&root, path); err != nil {
+		return nil, fmt.Errorf("LoadYAML: %w", err)
+	}
+
+	var ext externalPipeline
+	if err := root.Decode(&ext); err != nil {
+		return nil, fmt.Errorf("LoadYAML: decoding %q: %w", path, err)
+	}
+
+	p := New()
+	applyExternalPipeline(p, &ext)
+	return p, nil
+}
+
+// Marshal renders p back to the same YAML shape LoadYAML reads, through
+// the externalPipeline schema - the same subset of fields LoadJsonnet,
+// LoadCUE and Include accept, so LoadYAML(Marshal(p)) round-trips tasks,
+// dependencies and matrix dimensions exactly. Anything outside that
+// schema (mount resources, Finally tasks, the artifact store, ...) is
+// dropped, same limitation Include already has for hand-written bundles.
+func Marshal(p *Pipeline) ([]byte, error) {
+	doc, err := p.buildDoc()
+	if err != nil {
+		return nil, fmt.Errorf("Marshal: %w", err)
+	}
+
+	ext := externalPipeline{Tasks: make([]externalTask, len(doc.Tasks))}
+	for i, jt := range doc.Tasks {
+		ext.Tasks[i] = externalTask{
+			Name:      jt.Name,
+			Command:   jt.Command,
+			Container: jt.Container,
+			Workdir:   jt.Workdir,
+			Env:       jt.Env,
+			Inputs:    jt.Inputs,
+			Outputs:   jt.Outputs,
+			DependsOn: jt.DependsOn,
+			When:      jt.When,
+			Secrets:   jt.Secrets,
+			Matrix:    matrixDimensionsOnly(jt.Matrix),
+			Retry:     jt.Retry,
+			Timeout:   jt.Timeout,
+		}
+	}
+
+	// Route through JSON first so the yaml.v3 encoder honors the json
+	// tags above instead of its own default lowercased field names - the
+	// same trick encodeYAML uses for EmitTo's YAML output.
+	asJSON, err := json.Marshal(ext)
+	if err != nil {
+		return nil, fmt.Errorf("Marshal: %w", err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(asJSON, &generic); err != nil {
+		return nil, fmt.Errorf("Marshal: %w", err)
+	}
+	return yaml.Marshal(generic)
+}
+
+// matrixDimensionsOnly strips the "exclude"/"include"/"combinations"
+// pseudo-keys EmitTo folds into a task's Matrix map, leaving only the
+// real dimensions externalTask.Matrix expects.
+func matrixDimensionsOnly(m map[string]interface{}) map[string][]string {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string][]string, len(m))
+	for k, v := range m {
+		switch k {
+		case "exclude", "include", "combinations":
+			continue
+		default:
+			if values := toStringSlice(v); len(values) > 0 {
+				out[k] = values
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// decodeYAMLFile reads path and decodes its single document into root,
+// preserving the raw node tree (rather than decoding straight into
+// externalPipeline) so resolveIncludes and validateExternalYAML can walk
+// it first.
+func decodeYAMLFile(path string, root *yaml.Node) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %q: %w", path, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing %q: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return fmt.Errorf("%q is empty", path)
+	}
+	*root = *doc.Content[0]
+	return nil
+}
+
+// resolveIncludes walks node depth-first, replacing any node tagged
+// "!include <path>" with the parsed content of that path (resolved
+// relative to baseDir), recursively - an included file may itself include
+// further files.
+func resolveIncludes(node *yaml.Node, baseDir string) error {
+	if node.Tag == "!include" {
+		incPath := node.Value
+		if !filepath.IsAbs(incPath) {
+			incPath = filepath.Join(baseDir, incPath)
+		}
+		var included yaml.Node
+		if err := decodeYAMLFile(incPath, &included); err != nil {
+			return err
+		}
+		if err := resolveIncludes(&included, filepath.Dir(incPath)); err != nil {
+			return err
+		}
+		*node = included
+		return nil
+	}
+	for _, child := range node.Content {
+		if err := resolveIncludes(child, baseDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateExternalYAML walks root's "tasks" sequence, checking each entry
+// has the fields applyExternalPipeline requires, and reports failures
+// with the offending node's line/column so an author can jump straight
+// to the mistake instead of guessing from a generic unmarshal error.
+func validateExternalYAML(root *yaml.Node, path string) error {
+	tasksNode := mappingValue(root, "tasks")
+	if tasksNode == nil {
+		return fmt.Errorf("%s:%d:%d: missing required \"tasks\" list", path, root.Line, root.Column)
+	}
+	if tasksNode.Kind != yaml.SequenceNode {
+		return fmt.Errorf("%s:%d:%d: \"tasks\" must be a list", path, tasksNode.Line, tasksNode.Column)
+	}
+	for _, taskNode := range tasksNode.Content {
+		name := mappingValue(taskNode, "name")
+		if name == nil || name.Value == "" {
+			return fmt.Errorf("%s:%d:%d: task missing required \"name\" field", path, taskNode.Line, taskNode.Column)
+		}
+		if mappingValue(taskNode, "command") == nil {
+			return fmt.Errorf("%s:%d:%d: task %q missing required \"command\" field", path, taskNode.Line, taskNode.Column, name.Value)
+		}
+	}
+	return nil
+}
+
+// mappingValue returns the value node for key in node, or nil if node
+// isn't a mapping or has no such key.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package sykli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testYAMLPipeline = `
+tasks:
+  - name: build
+    command: go build ./...
+  - name: test
+    command: go test ./...
+    depends_on: [build]
+`
+
+func TestLoadYAMLBuildsPipeline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ci.yaml")
+	if err := os.WriteFile(path, []byte(testYAMLPipeline), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadYAML(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test := findTaskByName(result, "test")
+	deps := test["depends_on"].([]interface{})
+	if len(deps) != 1 || deps[0] != "build" {
+		t.Errorf("expected test to depend on build, got %v", deps)
+	}
+}
+
+func TestLoadYAMLMissingCommandReportsLineColumn(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ci.yaml")
+	src := "tasks:\n  - name: build\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadYAML(path)
+	if err == nil {
+		t.Fatal("expected error for task missing command")
+	}
+	if !strings.Contains(err.Error(), "2:5") {
+		t.Errorf("expected error to point at line 2 column 5, got %q", err.Error())
+	}
+}
+
+func TestLoadYAMLResolvesInclude(t *testing.T) {
+	dir := t.TempDir()
+	common := filepath.Join(dir, "common-tasks.yaml")
+	if err := os.WriteFile(common, []byte("- name: lint\n  command: golangci-lint run\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	main := filepath.Join(dir, "ci.yaml")
+	if err := os.WriteFile(main, []byte("tasks: !include common-tasks.yaml\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadYAML(main)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findTaskByName(result, "lint") == nil {
+		t.Error("expected included lint task")
+	}
+}
+
+func TestLoadYAMLSupportsAnchors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ci.yaml")
+	src := `
+env: &common
+  CI: "true"
+tasks:
+  - name: build
+    command: go build ./...
+    env: *common
+`
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadYAML(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := emitJSON(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	build := findTaskByName(result, "build")
+	env := build["env"].(map[string]interface{})
+	if env["CI"] != "true" {
+		t.Errorf("expected anchor-resolved env CI=true, got %+v", env)
+	}
+}
+
+func TestMarshalLoadYAMLRoundTrip(t *testing.T) {
+	p := New()
+	p.Task("build").Run("go build ./...")
+	p.Task("test").Run("go test ./...").After("build").Env("CI", "true")
+
+	out, err := Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "roundtrip.yaml")
+	if err := os.WriteFile(path, out, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p2, err := LoadYAML(path)
+	if err != nil {
+		t.Fatalf("LoadYAML(Marshal(p)) failed: %v", err)
+	}
+	result, err := emitJSON(p2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test := findTaskByName(result, "test")
+	deps := test["depends_on"].([]interface{})
+	if len(deps) != 1 || deps[0] != "build" {
+		t.Errorf("expected round-tripped test to depend on build, got %v", deps)
+	}
+}